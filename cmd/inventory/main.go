@@ -20,23 +20,26 @@ import (
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 
+	"github.com/pkedy/golang-dapr/pkg/components/bindings"
+	"github.com/pkedy/golang-dapr/pkg/components/pubsub"
 	"github.com/pkedy/golang-dapr/pkg/components/secrets"
 	"github.com/pkedy/golang-dapr/pkg/components/state"
 	"github.com/pkedy/golang-dapr/pkg/connect/postgres"
 	"github.com/pkedy/golang-dapr/pkg/dapr"
 	"github.com/pkedy/golang-dapr/pkg/errorz"
+	"github.com/pkedy/golang-dapr/pkg/features"
+	deadletters_repo "github.com/pkedy/golang-dapr/pkg/features/deadletters/repository"
 	gadgets_repo "github.com/pkedy/golang-dapr/pkg/features/gadgets/repository"
-	gadgets_service "github.com/pkedy/golang-dapr/pkg/features/gadgets/service"
 	products_repo "github.com/pkedy/golang-dapr/pkg/features/products/repository"
-	products_service "github.com/pkedy/golang-dapr/pkg/features/products/service"
 	widgets_repo "github.com/pkedy/golang-dapr/pkg/features/widgets/repository"
-	widgets_service "github.com/pkedy/golang-dapr/pkg/features/widgets/service"
 )
 
 // api is an interface to embed all the components.
 type api interface {
 	secrets.Store
 	state.Store
+	pubsub.Publisher
+	bindings.Invoker
 	Name() string
 }
 
@@ -53,6 +56,7 @@ func main() {
 
 	clientType := "sdk"
 
+	gadgetTTL := flag.Duration("gadget-ttl", 0, "expire gadgets this long after they are saved (0 keeps them)")
 	flag.Parse()
 	args := flag.Args()
 	if len(args) > 0 {
@@ -99,13 +103,19 @@ func main() {
 
 	// Wire up dependencies
 
+	// Records processed events in the state store for a day
+	dedup := dapr.NewDedup(log, daprClient, "statestore", 24*time.Hour)
+
 	// Uses Postgres database
 	widgetRepo := widgets_repo.New(log, pool)
-	widgetRest := widgets_service.New(log, widgetRepo)
+	relay := widgets_repo.NewRelay(log, pool, daprClient, "pubsub", time.Second)
 
 	// Uses state store
-	gadgetRepo := gadgets_repo.New(log, daprClient, "statestore")
-	gadgetRest := gadgets_service.New(log, gadgetRepo)
+	var gadgetOptions []state.Option
+	if *gadgetTTL > 0 {
+		gadgetOptions = append(gadgetOptions, state.WithTTL(*gadgetTTL))
+	}
+	gadgetRepo := gadgets_repo.New(log, daprClient, "statestore", gadgetOptions...)
 
 	// Uses service invocation
 	productRepo, err := products_repo.New(log)
@@ -114,7 +124,17 @@ func main() {
 		os.Exit(1)
 	}
 	defer productRepo.Close()
-	productRest := products_service.New(log, productRepo)
+
+	services := features.NewServices(log, features.Dependencies{
+		Widgets:      widgetRepo,
+		WidgetOutbox: relay,
+		Gadgets:      gadgetRepo,
+		Products:     productRepo,
+		// Keeps undeliverable events in the state store
+		DeadLetters: deadletters_repo.New(log, daprClient, "statestore"),
+		Publisher:   daprClient,
+		Dedup:       dedup,
+	})
 
 	// Fiber app config with custom error handler
 	config := fiber.Config{
@@ -129,8 +149,7 @@ func main() {
 	// Public REST API operations
 	{
 		app := fiber.New(config)
-		dapr.RegisterServices(app,
-			widgetRest, gadgetRest, productRest)
+		dapr.RegisterServices(app, services)
 		g.Add(func() error {
 			return app.Listen(":3000")
 		}, func(err error) {
@@ -166,15 +185,20 @@ func main() {
 	//         - match: "event.type == 'gadget.v1'"
 	//           path: /gadgets
 	//       default: /products
+	//     deadLetterTopic: inventory-deadletter
 	//
 
 	// Custom - HTTP events handlers
 	{
 		app := fiber.New(config)
-		dapr.RegisterEventHandlers(app,
-			widgetRest, gadgetRest, productRest)
-		dapr.Subscribe(log, dapr.SubscribeHTTPHandler(log, app),
-			widgetRest, gadgetRest, productRest)
+		dapr.RegisterEventHandlers(app, services)
+		dapr.RegisterInvokeHandlers(app, services.Widgets)
+		dapr.RegisterBindings(app, services.Widgets)
+		if err := dapr.Subscribe(log, dapr.SubscribeHTTPHandler(log, app),
+			services.Subscribers()...); err != nil {
+			log.Error(err, "invalid subscriptions")
+			os.Exit(1)
+		}
 		g.Add(func() error {
 			return app.Listen(":3001")
 		}, func(err error) {
@@ -185,10 +209,13 @@ func main() {
 	{
 		gs := grpc.NewServer()
 		server := dapr.NewServer(log)
-		server.RegisterTopicEventHandlers(
-			widgetRest, gadgetRest, productRest)
-		dapr.Subscribe(log, server.Subscribe,
-			widgetRest, gadgetRest, productRest)
+		server.RegisterTopicEventHandlers(services)
+		server.RegisterInvokeHandlers(services.Widgets)
+		server.RegisterBindingHandlers(services.Widgets)
+		if err := dapr.Subscribe(log, server, services.Subscribers()...); err != nil {
+			log.Error(err, "invalid subscriptions")
+			os.Exit(1)
+		}
 		pb.RegisterAppCallbackServer(gs, server)
 		g.Add(func() error {
 			ln, err := net.Listen("tcp", ":4001")
@@ -206,9 +233,8 @@ func main() {
 		g.Add(func() error {
 			s = dapr_server_http.NewService(":3002")
 			err = multierr.Combine(
-				widgetRest.RegisterTopicEventHandlersSDK(s),
-				gadgetRest.RegisterTopicEventHandlersSDK(s),
-				productRest.RegisterTopicEventHandlersSDK(s))
+				services.RegisterTopicEventHandlersSDK(s),
+				dapr.RegisterBindingsSDK(s, services.Widgets))
 			if err != nil {
 				return err
 			}
@@ -228,9 +254,8 @@ func main() {
 				return err
 			}
 			err = multierr.Combine(
-				widgetRest.RegisterTopicEventHandlersSDK(s),
-				gadgetRest.RegisterTopicEventHandlersSDK(s),
-				productRest.RegisterTopicEventHandlersSDK(s))
+				services.RegisterTopicEventHandlersSDK(s),
+				dapr.RegisterBindingsSDK(s, services.Widgets))
 			if err != nil {
 				return err
 			}
@@ -241,6 +266,15 @@ func main() {
 			}
 		})
 	}
+	// Publishes widget events from the outbox
+	{
+		ctx, cancel := context.WithCancel(ctx)
+		g.Add(func() error {
+			return relay.Run(ctx)
+		}, func(err error) {
+			cancel()
+		})
+	}
 	// Termination signals
 	{
 		g.Add(run.SignalHandler(ctx, os.Interrupt, os.Kill))
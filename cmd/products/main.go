@@ -4,18 +4,22 @@ import (
 	"context"
 	"log"
 	"net"
+	"sort"
 	"sync"
 
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 
+	"github.com/pkedy/golang-dapr/pkg/errorz"
+	"github.com/pkedy/golang-dapr/pkg/validation"
 	pb "github.com/pkedy/golang-dapr/proto/products"
 )
 
 const (
 	port = ":50151"
+
+	defaultPageSize = 100
+	maxPageSize     = 1000
 )
 
 // server is used to implement helloworld.GreeterServer.
@@ -38,14 +42,33 @@ func (s *server) GetProduct(ctx context.Context, in *pb.ProductRequest) (*pb.Pro
 
 	product, ok := s.products[in.Id]
 	if !ok {
-		return nil, status.Errorf(codes.NotFound, "product %q not found", in.Id)
+		return nil, errorz.NotFound("product %q not found", in.Id)
 	}
 
 	return product, nil
 }
 
+func (s *server) CreateProduct(ctx context.Context, product *pb.Product) (*emptypb.Empty, error) {
+	log.Println("CreateProduct called", product)
+	if err := validation.Item("product", product.Id, product.Description, product.Price); err != nil {
+		return nil, err
+	}
+	s.Lock()
+	defer s.Unlock()
+
+	if _, ok := s.products[product.Id]; ok {
+		return nil, errorz.AlreadyExists("product %q already exists", product.Id)
+	}
+	s.products[product.Id] = product
+
+	return &emptypb.Empty{}, nil
+}
+
 func (s *server) SaveProduct(ctx context.Context, product *pb.Product) (*emptypb.Empty, error) {
 	log.Println("SaveProduct called", product)
+	if err := validation.Item("product", product.Id, product.Description, product.Price); err != nil {
+		return nil, err
+	}
 	s.Lock()
 	defer s.Unlock()
 
@@ -54,12 +77,66 @@ func (s *server) SaveProduct(ctx context.Context, product *pb.Product) (*emptypb
 	return &emptypb.Empty{}, nil
 }
 
+func (s *server) DeleteProduct(ctx context.Context, in *pb.ProductRequest) (*emptypb.Empty, error) {
+	log.Println("DeleteProduct called", in.Id)
+	s.Lock()
+	defer s.Unlock()
+
+	if _, ok := s.products[in.Id]; !ok {
+		return nil, errorz.NotFound("product %q not found", in.Id)
+	}
+	delete(s.products, in.Id)
+
+	return &emptypb.Empty{}, nil
+}
+
+// ListProducts returns products ordered by ID. The page token
+// is the ID of the last product of the previous page.
+func (s *server) ListProducts(ctx context.Context, in *pb.ListProductsRequest) (*pb.ListProductsResponse, error) {
+	log.Println("ListProducts called", in.PageSize, in.PageToken)
+	pageSize := int(in.PageSize)
+	if pageSize < 0 {
+		return nil, errorz.InvalidArgument("invalid page size %d", in.PageSize)
+	}
+	switch {
+	case pageSize == 0:
+		pageSize = defaultPageSize
+	case pageSize > maxPageSize:
+		pageSize = maxPageSize
+	}
+
+	s.RLock()
+	defer s.RUnlock()
+
+	ids := make([]string, 0, len(s.products))
+	for id := range s.products {
+		if id > in.PageToken {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	var resp pb.ListProductsResponse
+	if len(ids) > pageSize {
+		ids = ids[:pageSize]
+		resp.NextPageToken = ids[pageSize-1]
+	}
+	resp.Products = make([]*pb.Product, len(ids))
+	for i, id := range ids {
+		resp.Products[i] = s.products[id]
+	}
+
+	return &resp, nil
+}
+
 func main() {
 	lis, err := net.Listen("tcp", port)
 	if err != nil {
 		log.Fatalf("failed to listen: %v", err)
 	}
-	s := grpc.NewServer()
+	s := grpc.NewServer(
+		grpc.UnaryInterceptor(errorz.UnaryServerInterceptor()),
+	)
 	pb.RegisterProductsServer(s, newServer())
 	log.Printf("server listening at %v", lis.Addr())
 	if err := s.Serve(lis); err != nil {
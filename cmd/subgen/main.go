@@ -0,0 +1,215 @@
+// Command subgen writes declarative Dapr Subscription resources
+// for the subscriptions that the inventory features register in code.
+//
+// Usage:
+//
+//	subgen [-scopes inventory-disabled] [-o components/subscription.yaml]
+//	subgen -check components/subscription.yaml
+//
+// The inventory app also subscribes programmatically, so by default the
+// subscriptions are scoped to an app ID that does not exist. Use
+// `-scopes inventory` to use the declarative subscriptions instead.
+//
+// With `-check`, the generated YAML is compared to the file and
+// subgen exits with status 1 if they differ.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"gopkg.in/yaml.v3"
+
+	"github.com/pkedy/golang-dapr/pkg/dapr"
+	"github.com/pkedy/golang-dapr/pkg/features"
+)
+
+const header = "# Code generated by subgen. DO NOT EDIT.\n"
+
+// Dapr declarative subscription (dapr.io/v2alpha1)
+type (
+	resource struct {
+		APIVersion string   `yaml:"apiVersion"`
+		Kind       string   `yaml:"kind"`
+		Metadata   metadata `yaml:"metadata"`
+		Spec       spec     `yaml:"spec"`
+		Scopes     []string `yaml:"scopes,omitempty"`
+	}
+
+	metadata struct {
+		Name string `yaml:"name"`
+	}
+
+	spec struct {
+		PubsubName      string            `yaml:"pubsubname"`
+		Topic           string            `yaml:"topic"`
+		Metadata        map[string]string `yaml:"metadata,omitempty"`
+		Routes          routes            `yaml:"routes"`
+		DeadLetterTopic string            `yaml:"deadLetterTopic,omitempty"`
+		BulkSubscribe   *bulkSubscribe    `yaml:"bulkSubscribe,omitempty"`
+	}
+
+	routes struct {
+		Rules   []rule `yaml:"rules,omitempty"`
+		Default string `yaml:"default,omitempty"`
+	}
+
+	rule struct {
+		Match string `yaml:"match"`
+		Path  string `yaml:"path"`
+	}
+
+	bulkSubscribe struct {
+		Enabled            bool  `yaml:"enabled"`
+		MaxMessagesCount   int32 `yaml:"maxMessagesCount,omitempty"`
+		MaxAwaitDurationMs int32 `yaml:"maxAwaitDurationMs,omitempty"`
+	}
+)
+
+func main() {
+	var scopes, output, check string
+	flag.StringVar(&scopes, "scopes", "inventory-disabled", "comma-separated app IDs that use the subscriptions")
+	flag.StringVar(&output, "o", "", "output file (defaults to stdout)")
+	flag.StringVar(&check, "check", "", "compare the generated YAML to this file")
+	flag.Parse()
+
+	// The services only need their dependencies to handle
+	// events, not to list their subscriptions.
+	log := logr.Discard()
+	services := features.NewServices(log, features.Dependencies{})
+	subscriptions, err := dapr.MergeSubscriptions(services.Subscribers()...)
+	if err == nil {
+		err = dapr.ValidateSubscriptions(subscriptions, nil)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "invalid subscriptions:", err)
+		os.Exit(1)
+	}
+
+	generated, err := generate(subscriptions, split(scopes))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "could not generate YAML:", err)
+		os.Exit(1)
+	}
+
+	switch {
+	case check != "":
+		existing, err := os.ReadFile(check)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if !bytes.Equal(existing, generated) {
+			fmt.Fprintf(os.Stderr, "%s is out of date (run subgen -o %s)\n", check, check)
+			fmt.Fprint(os.Stderr, diff(string(existing), string(generated)))
+			os.Exit(1)
+		}
+	case output != "":
+		if err := os.WriteFile(output, generated, 0o644); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	default:
+		os.Stdout.Write(generated)
+	}
+}
+
+// generate writes a YAML document per subscription.
+func generate(subscriptions []*dapr.Subscription, scopes []string) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(header)
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	for _, sub := range subscriptions {
+		res := resource{
+			APIVersion: "dapr.io/v2alpha1",
+			Kind:       "Subscription",
+			Metadata: metadata{
+				Name: sub.PubsubName + "-" + sub.Topic,
+			},
+			Spec: spec{
+				PubsubName:      sub.PubsubName,
+				Topic:           sub.Topic,
+				Metadata:        sub.Metadata,
+				DeadLetterTopic: sub.DeadLetterTopic,
+				Routes: routes{
+					Default: sub.Routes.Default,
+				},
+			},
+			Scopes: scopes,
+		}
+		for _, r := range sub.Routes.Rules {
+			res.Spec.Routes.Rules = append(res.Spec.Routes.Rules, rule{
+				Match: r.Match,
+				Path:  r.Path,
+			})
+		}
+		if b := sub.BulkSubscribe; b != nil {
+			res.Spec.BulkSubscribe = &bulkSubscribe{
+				Enabled:            b.Enabled,
+				MaxMessagesCount:   b.MaxMessagesCount,
+				MaxAwaitDurationMs: b.MaxAwaitDurationMs,
+			}
+		}
+		if err := enc.Encode(&res); err != nil {
+			return nil, err
+		}
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func split(s string) []string {
+	var values []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// diff returns the lines that were removed (-) from `a`
+// and added (+) in `b`, based on their longest common subsequence.
+func diff(a, b string) string {
+	x := strings.Split(a, "\n")
+	y := strings.Split(b, "\n")
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var sb strings.Builder
+	i, j := 0, 0
+	for i < len(x) || j < len(y) {
+		switch {
+		case i < len(x) && j < len(y) && x[i] == y[j]:
+			i++
+			j++
+		case j < len(y) && (i == len(x) || lcs[i][j+1] >= lcs[i+1][j]):
+			fmt.Fprintf(&sb, "+ %s\n", y[j])
+			j++
+		default:
+			fmt.Fprintf(&sb, "- %s\n", x[i])
+			i++
+		}
+	}
+	return sb.String()
+}
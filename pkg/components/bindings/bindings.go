@@ -0,0 +1,51 @@
+package bindings
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Common operations supported by output bindings.
+const (
+	OperationCreate = "create"
+	OperationGet    = "get"
+	OperationDelete = "delete"
+	OperationList   = "list"
+)
+
+type Invoker interface {
+	InvokeBinding(ctx context.Context, name string, req *Request) (*Response, error)
+}
+
+// Request is sent to an output binding. Byte slices and strings in `Data`
+// are sent as is. Anything else is serialized as JSON.
+type Request struct {
+	Operation string
+	Data      interface{}
+	Metadata  map[string]string
+}
+
+// Response is returned by an output binding. `Data` is empty
+// for operations that do not return anything.
+type Response struct {
+	Data     []byte
+	Metadata map[string]string
+}
+
+// Bytes returns the serialized request data.
+func (r *Request) Bytes() ([]byte, error) {
+	switch d := r.Data.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		return d, nil
+	case string:
+		return []byte(d), nil
+	}
+	return json.Marshal(r.Data)
+}
+
+// Decode unmarshals the response data into `target`.
+func (r *Response) Decode(target interface{}) error {
+	return json.Unmarshal(r.Data, target)
+}
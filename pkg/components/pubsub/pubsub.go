@@ -0,0 +1,90 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+const (
+	ContentTypeJSON       = "application/json"
+	ContentTypeCloudEvent = "application/cloudevents+json"
+
+	MetadataTTL          = "ttlInSeconds"
+	MetadataPartitionKey = "partitionKey"
+	MetadataRawPayload   = "rawPayload"
+)
+
+type Publisher interface {
+	PublishEvent(ctx context.Context, pubsub string, topic string, data interface{}, opts ...Option) error
+}
+
+// Options are applied when publishing an event.
+type Options struct {
+	ContentType string
+	Metadata    map[string]string
+}
+
+type Option func(*Options)
+
+func NewOptions(opts ...Option) Options {
+	var options Options
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+func WithContentType(contentType string) Option {
+	return func(o *Options) {
+		o.ContentType = contentType
+	}
+}
+
+func WithMetadata(key, value string) Option {
+	return func(o *Options) {
+		if o.Metadata == nil {
+			o.Metadata = make(map[string]string)
+		}
+		o.Metadata[key] = value
+	}
+}
+
+// WithCloudEvent publishes `data` as a complete CloudEvent
+// instead of having Dapr wrap it in an envelope.
+func WithCloudEvent() Option {
+	return WithContentType(ContentTypeCloudEvent)
+}
+
+// WithRawPayload publishes `data` without a CloudEvent envelope.
+func WithRawPayload() Option {
+	return WithMetadata(MetadataRawPayload, "true")
+}
+
+// WithTTL expires the message after `ttl`, rounded down to the second.
+func WithTTL(ttl time.Duration) Option {
+	return WithMetadata(MetadataTTL, strconv.Itoa(int(ttl/time.Second)))
+}
+
+func WithPartitionKey(key string) Option {
+	return WithMetadata(MetadataPartitionKey, key)
+}
+
+// Encode returns the bytes to publish for `data` and their content type.
+// Byte slices and strings are sent as is. Anything else is serialized as JSON.
+func Encode(data interface{}, contentType string) ([]byte, string, error) {
+	switch d := data.(type) {
+	case nil:
+		return nil, contentType, nil
+	case []byte:
+		return d, contentType, nil
+	case string:
+		return []byte(d), contentType, nil
+	}
+	if contentType == "" {
+		contentType = ContentTypeJSON
+	}
+	dataBytes, err := json.Marshal(data)
+	return dataBytes, contentType, err
+}
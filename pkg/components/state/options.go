@@ -0,0 +1,80 @@
+package state
+
+import (
+	"strconv"
+	"time"
+)
+
+type (
+	Consistency string
+	Concurrency string
+)
+
+const (
+	ConsistencyEventual Consistency = "eventual"
+	ConsistencyStrong   Consistency = "strong"
+
+	ConcurrencyFirstWrite Concurrency = "first-write"
+	ConcurrencyLastWrite  Concurrency = "last-write"
+
+	// MetadataTTL is the metadata key Dapr uses for state expiration.
+	MetadataTTL = "ttlInSeconds"
+)
+
+// Options are applied to a state request. Empty values use the state store's defaults.
+type Options struct {
+	Consistency Consistency
+	Concurrency Concurrency
+	Metadata    map[string]string
+}
+
+type Option func(*Options)
+
+func NewOptions(opts ...Option) Options {
+	var options Options
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+func WithConsistency(consistency Consistency) Option {
+	return func(o *Options) {
+		o.Consistency = consistency
+	}
+}
+
+func WithConcurrency(concurrency Concurrency) Option {
+	return func(o *Options) {
+		o.Concurrency = concurrency
+	}
+}
+
+func WithMetadata(key, value string) Option {
+	return func(o *Options) {
+		if o.Metadata == nil {
+			o.Metadata = make(map[string]string)
+		}
+		o.Metadata[key] = value
+	}
+}
+
+// WithTTL expires the saved state after `ttl`, rounded down to the second.
+func WithTTL(ttl time.Duration) Option {
+	return WithMetadata(MetadataTTL, strconv.Itoa(int(ttl/time.Second)))
+}
+
+// MergeMetadata returns the options metadata overlaid with `metadata`.
+func (o *Options) MergeMetadata(metadata map[string]string) map[string]string {
+	if len(o.Metadata) == 0 {
+		return metadata
+	}
+	merged := make(map[string]string, len(o.Metadata)+len(metadata))
+	for k, v := range o.Metadata {
+		merged[k] = v
+	}
+	for k, v := range metadata {
+		merged[k] = v
+	}
+	return merged
+}
@@ -0,0 +1,105 @@
+package state
+
+// Query is a state query in the format of Dapr's state query API.
+// See https://docs.dapr.io/developing-applications/building-blocks/state-management/howto-state-query-api/
+type Query struct {
+	Filter Filter `json:"filter,omitempty"`
+	Sort   []Sort `json:"sort,omitempty"`
+	Page   *Page  `json:"page,omitempty"`
+}
+
+// Filter is a single filter operation. Use the functions below to create them.
+type Filter map[string]interface{}
+
+type Order string
+
+const (
+	OrderAsc  Order = "ASC"
+	OrderDesc Order = "DESC"
+)
+
+type Sort struct {
+	Key   string `json:"key"`
+	Order Order  `json:"order,omitempty"`
+}
+
+type Page struct {
+	Limit int    `json:"limit,omitempty"`
+	Token string `json:"token,omitempty"`
+}
+
+// QueryResult is a page of query results. `Token` is set if there are more results.
+type QueryResult struct {
+	Results  []BulkItem        `json:"results"`
+	Token    string            `json:"token,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+func NewQuery(filter Filter) *Query {
+	return &Query{
+		Filter: filter,
+	}
+}
+
+func (q *Query) SortBy(key string, order Order) *Query {
+	q.Sort = append(q.Sort, Sort{
+		Key:   key,
+		Order: order,
+	})
+	return q
+}
+
+func (q *Query) Limit(limit int) *Query {
+	if q.Page == nil {
+		q.Page = &Page{}
+	}
+	q.Page.Limit = limit
+	return q
+}
+
+// Continue sets the continuation token from a previous `QueryResult`.
+func (q *Query) Continue(token string) *Query {
+	if q.Page == nil {
+		q.Page = &Page{}
+	}
+	q.Page.Token = token
+	return q
+}
+
+func Eq(key string, value interface{}) Filter {
+	return op("EQ", key, value)
+}
+
+func In(key string, values ...interface{}) Filter {
+	return op("IN", key, values)
+}
+
+func And(filters ...Filter) Filter {
+	return combine("AND", filters)
+}
+
+func Or(filters ...Filter) Filter {
+	return combine("OR", filters)
+}
+
+func op(name, key string, value interface{}) Filter {
+	return Filter{
+		name: map[string]interface{}{
+			key: value,
+		},
+	}
+}
+
+// combine returns the single filter unwrapped because
+// Dapr requires at least two operands for AND/OR.
+func combine(name string, filters []Filter) Filter {
+	switch len(filters) {
+	case 0:
+		return nil
+	case 1:
+		return filters[0]
+	}
+	return Filter{
+		name: filters,
+	}
+}
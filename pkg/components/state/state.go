@@ -2,15 +2,78 @@ package state
 
 import (
 	"context"
+	"encoding/json"
 )
 
 type Item struct {
-	Key   string      `json:"key"`
-	Value interface{} `json:"value"`
-	ETag  string      `json:"etag,omitempty"`
+	Key      string            `json:"key"`
+	Value    interface{}       `json:"value,omitempty"`
+	ETag     string            `json:"etag,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+type OperationType string
+
+const (
+	OperationUpsert OperationType = "upsert"
+	OperationDelete OperationType = "delete"
+)
+
+// Operation is a single upsert or delete performed by `ExecuteStateTransaction`.
+type Operation struct {
+	Type OperationType `json:"operation"`
+	Item Item          `json:"request"`
+}
+
+// BulkItem is a single result from `GetBulkState`.
+// `Value` is left encoded so callers can decode each item into their own type.
+type BulkItem struct {
+	Key   string          `json:"key"`
+	Value json.RawMessage `json:"data,omitempty"`
+	ETag  string          `json:"etag,omitempty"`
+	Error string          `json:"error,omitempty"`
 }
 
 type Store interface {
-	SetState(ctx context.Context, store string, items ...Item) error
-	GetState(ctx context.Context, store string, key string, target interface{}) error
+	SetState(ctx context.Context, store string, items []Item, opts ...Option) error
+	GetState(ctx context.Context, store string, key string, target interface{}, opts ...Option) error
+	GetStateWithETag(ctx context.Context, store string, key string, target interface{}, opts ...Option) (etag string, err error)
+	// GetBulkState only applies the metadata of `opts`,
+	// because Dapr does not support consistency for bulk reads.
+	GetBulkState(ctx context.Context, store string, keys []string, opts ...Option) ([]BulkItem, error)
+	DeleteState(ctx context.Context, store string, key string, etag string, opts ...Option) error
+	// ExecuteStateTransaction applies `opts` to every operation, as `SetState`
+	// does to every item, and also sends their metadata with the transaction.
+	ExecuteStateTransaction(ctx context.Context, store string, ops []Operation, opts ...Option) error
+	Query(ctx context.Context, store string, query *Query, opts ...Option) (*QueryResult, error)
+}
+
+// Upsert returns a transaction operation that saves `item`.
+func Upsert(item Item) Operation {
+	return Operation{
+		Type: OperationUpsert,
+		Item: item,
+	}
+}
+
+// Delete returns a transaction operation that removes `key`.
+// If `etag` is not empty, the delete only succeeds if it matches.
+func Delete(key string, etag string) Operation {
+	return Operation{
+		Type: OperationDelete,
+		Item: Item{
+			Key:  key,
+			ETag: etag,
+		},
+	}
+}
+
+// Found returns true if the key exists in the state store.
+func (i *BulkItem) Found() bool {
+	return len(i.Value) > 0 && string(i.Value) != "null"
+}
+
+// Decode unmarshals the item's value into `target`.
+func (i *BulkItem) Decode(target interface{}) error {
+	return json.Unmarshal(i.Value, target)
 }
@@ -0,0 +1,78 @@
+package dapr
+
+import (
+	"context"
+
+	"github.com/dapr/go-sdk/service/common"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/multierr"
+)
+
+type (
+	// BindingEvent is an input binding event received from Dapr
+	// over either HTTP or gRPC.
+	BindingEvent struct {
+		Name     string
+		Data     []byte
+		Metadata map[string]string
+	}
+
+	// BindingHandler handles events from an input binding. The returned
+	// data, if any, is sent back to Dapr as the response.
+	BindingHandler func(ctx context.Context, in *BindingEvent) ([]byte, error)
+
+	// RegisterBindingHandler registers `handler` for the input binding `name`.
+	RegisterBindingHandler func(name string, handler BindingHandler)
+
+	BindingRegistrar interface {
+		RegisterBindingHandlers(register RegisterBindingHandler)
+	}
+)
+
+// RegisterBindings mounts the input binding handlers of `registrars`
+// on `app` at the paths Dapr uses to deliver binding events over HTTP.
+func RegisterBindings(app *fiber.App, registrars ...BindingRegistrar) {
+	for _, r := range registrars {
+		r.RegisterBindingHandlers(func(name string, handler BindingHandler) {
+			HandleBinding(app, name, bindingHandlerHTTP(name, handler))
+		})
+	}
+}
+
+// RegisterBindingsSDK adds the input binding handlers of `registrars` to an SDK service.
+func RegisterBindingsSDK(s common.Service, registrars ...BindingRegistrar) error {
+	var errs error
+	for _, r := range registrars {
+		r.RegisterBindingHandlers(func(name string, handler BindingHandler) {
+			errs = multierr.Append(errs, s.AddBindingInvocationHandler(name,
+				func(ctx context.Context, in *common.BindingEvent) ([]byte, error) {
+					return handler(ctx, &BindingEvent{
+						Name:     name,
+						Data:     in.Data,
+						Metadata: in.Metadata,
+					})
+				}))
+		})
+	}
+	return errs
+}
+
+// bindingHandlerHTTP adapts `handler` to fiber. Dapr sends
+// the binding metadata as request headers.
+func bindingHandlerHTTP(name string, handler BindingHandler) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		metadata := make(map[string]string)
+		c.Request().Header.VisitAll(func(key, value []byte) {
+			metadata[string(key)] = string(value)
+		})
+		out, err := handler(c.Context(), &BindingEvent{
+			Name:     name,
+			Data:     append([]byte(nil), c.Body()...),
+			Metadata: metadata,
+		})
+		if err != nil {
+			return err
+		}
+		return c.Send(out)
+	}
+}
@@ -0,0 +1,114 @@
+package dapr
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type (
+	// BulkMessage is a batch of messages delivered to a subscription
+	// with `BulkSubscribe` enabled.
+	BulkMessage struct {
+		ID         string             `json:"id"`
+		Entries    []BulkMessageEntry `json:"entries"`
+		Metadata   map[string]string  `json:"metadata,omitempty"`
+		PubsubName string             `json:"pubsubname"`
+		Topic      string             `json:"topic"`
+		Type       string             `json:"type"`
+	}
+
+	BulkMessageEntry struct {
+		EntryID     string            `json:"entryId"`
+		Event       json.RawMessage   `json:"event"`
+		ContentType string            `json:"contentType"`
+		Metadata    map[string]string `json:"metadata,omitempty"`
+	}
+
+	BulkEntryStatus struct {
+		EntryID string      `json:"entryId"`
+		Status  EventStatus `json:"status"`
+	}
+
+	BulkResponse struct {
+		Statuses []BulkEntryStatus `json:"statuses"`
+	}
+
+	// BulkEventHandler processes a single entry of a bulk message.
+	BulkEventHandler func(ctx context.Context, event *CloudEvent) EventStatus
+)
+
+// HandleBulk returns a fiber handler that passes each entry of a bulk
+// message to `handler` and responds with the status of every entry.
+func HandleBulk(handler BulkEventHandler) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var msg BulkMessage
+		if err := json.Unmarshal(c.Body(), &msg); err != nil {
+			return err
+		}
+		resp := BulkResponse{
+			Statuses: make([]BulkEntryStatus, len(msg.Entries)),
+		}
+		for i := range msg.Entries {
+			entry := &msg.Entries[i]
+			status := StatusDrop
+			if event, err := entry.CloudEvent(&msg); err == nil {
+				status = handler(c.Context(), event)
+			}
+			resp.Statuses[i] = BulkEntryStatus{
+				EntryID: entry.EntryID,
+				Status:  status,
+			}
+		}
+		return c.JSON(&resp)
+	}
+}
+
+// CloudEvent returns the entry as a validated CloudEvent. Raw payload
+// entries are wrapped in an event built from the attributes of `msg`.
+func (e *BulkMessageEntry) CloudEvent(msg *BulkMessage) (*CloudEvent, error) {
+	if parseMediaType(e.ContentType) == "application/cloudevents+json" {
+		var event CloudEvent
+		if err := json.Unmarshal(e.Event, &event); err != nil {
+			return nil, err
+		}
+		if err := event.Validate(); err != nil {
+			return nil, err
+		}
+		return &event, nil
+	}
+
+	eventType := msg.Type
+	if eventType == "" {
+		// The type Dapr uses when it wraps raw payloads.
+		eventType = "com.dapr.event.sent"
+	}
+	event := CloudEvent{
+		ID:              e.EntryID,
+		Source:          msg.PubsubName,
+		SpecVersion:     specVersion,
+		Type:            eventType,
+		DataContentType: e.ContentType,
+		Data:            e.Event,
+	}
+	// Binary raw payloads are delivered as a base64 string.
+	if !isJSON(e.ContentType) && len(e.Event) > 0 && e.Event[0] == '"' {
+		var encoded string
+		if err := json.Unmarshal(e.Event, &encoded); err != nil {
+			return nil, err
+		}
+		data, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			// Not base64, so it is plain text.
+			data = []byte(encoded)
+		}
+		event.Data = nil
+		event.DataBase64 = data
+	}
+	if err := event.Validate(); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
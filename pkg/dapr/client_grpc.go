@@ -12,6 +12,8 @@ import (
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
 
+	"github.com/pkedy/golang-dapr/pkg/components/bindings"
+	"github.com/pkedy/golang-dapr/pkg/components/pubsub"
 	"github.com/pkedy/golang-dapr/pkg/components/secrets"
 	"github.com/pkedy/golang-dapr/pkg/components/state"
 	"github.com/pkedy/golang-dapr/pkg/errorz"
@@ -24,8 +26,10 @@ type GRPC struct {
 var (
 	GRPCADDRESS = fmt.Sprintf("127.0.0.1:%s", os.Getenv("DAPR_GRPC_PORT"))
 
-	_ = state.Store((*HTTP)(nil))
-	_ = secrets.Store((*HTTP)(nil))
+	_ = state.Store((*GRPC)(nil))
+	_ = secrets.Store((*GRPC)(nil))
+	_ = pubsub.Publisher((*GRPC)(nil))
+	_ = bindings.Invoker((*GRPC)(nil))
 )
 
 func NewGRPC(ctx context.Context) (*GRPC, error) {
@@ -49,52 +53,185 @@ func (c *GRPC) Name() string {
 	return "Custom gRPC"
 }
 
-func (c *GRPC) SetState(ctx context.Context, store string, items ...state.Item) error {
+func (c *GRPC) SetState(ctx context.Context, store string, items []state.Item, opts ...state.Option) error {
+	options := state.NewOptions(opts...)
 	stateItems := make([]*v1.StateItem, len(items))
 	for i := range items {
-		item := items[i]
-		data, err := json.Marshal(item.Value)
+		stateItem, err := stateItemGRPC(&items[i], &options)
 		if err != nil {
-			return errorz.Internal(err, "could not serialize value for key %q", item.Key)
-		}
-		stateItems[i] = &v1.StateItem{
-			Key:   item.Key,
-			Etag:  etagGRPC(item.ETag),
-			Value: data,
+			return err
 		}
+		stateItems[i] = stateItem
 	}
-	c.client.SaveState(ctx, &pb.SaveStateRequest{
+	if _, err := c.client.SaveState(ctx, &pb.SaveStateRequest{
 		StoreName: store,
 		States:    stateItems,
-	})
+	}); err != nil {
+		return grpcError(err, "could not save state in store %q", store)
+	}
 	return nil
 }
 
-func (c *GRPC) GetState(ctx context.Context, store string, key string, target interface{}) error {
+func (c *GRPC) GetState(ctx context.Context, store string, key string, target interface{}, opts ...state.Option) error {
+	_, err := c.GetStateWithETag(ctx, store, key, target, opts...)
+	return err
+}
+
+func (c *GRPC) GetStateWithETag(ctx context.Context, store string, key string, target interface{}, opts ...state.Option) (string, error) {
+	options := state.NewOptions(opts...)
 	state, err := c.client.GetState(ctx, &pb.GetStateRequest{
 		StoreName:   store,
 		Key:         key,
-		Consistency: v1.StateOptions_CONSISTENCY_STRONG,
+		Consistency: consistencyGRPC(options.Consistency),
+		Metadata:    options.Metadata,
 	})
 	if err != nil {
-		return errorz.Internal(err, "could not load state %q", key)
+		return "", grpcError(err, "could not load state %q", key)
 	}
 	if state.Data == nil {
-		return errorz.NotFound("key %q not found", key)
+		return "", errorz.NotFound("key %q not found", key)
 	}
 	if err = json.Unmarshal(state.Data, target); err != nil {
-		return errorz.Internal(err, "could decode state %q", key)
+		return "", errorz.Internal(err, "could decode state %q", key)
+	}
+	return state.Etag, nil
+}
+
+func (c *GRPC) GetBulkState(ctx context.Context, store string, keys []string, opts ...state.Option) ([]state.BulkItem, error) {
+	options := state.NewOptions(opts...)
+	resp, err := c.client.GetBulkState(ctx, &pb.GetBulkStateRequest{
+		StoreName: store,
+		Keys:      keys,
+		Metadata:  options.Metadata,
+	})
+	if err != nil {
+		return nil, grpcError(err, "could not load bulk state")
+	}
+	items := make([]state.BulkItem, len(resp.Items))
+	for i, item := range resp.Items {
+		items[i] = state.BulkItem{
+			Key:   item.Key,
+			Value: item.Data,
+			ETag:  item.Etag,
+			Error: item.Error,
+		}
+	}
+	return items, nil
+}
+
+func (c *GRPC) DeleteState(ctx context.Context, store string, key string, etag string, opts ...state.Option) error {
+	options := state.NewOptions(opts...)
+	if _, err := c.client.DeleteState(ctx, &pb.DeleteStateRequest{
+		StoreName: store,
+		Key:       key,
+		Etag:      etagGRPC(etag),
+		Options:   stateOptionsGRPC(&options),
+		Metadata:  options.Metadata,
+	}); err != nil {
+		return grpcError(err, "could not delete key %q", key)
+	}
+	return nil
+}
+
+func (c *GRPC) ExecuteStateTransaction(ctx context.Context, store string, ops []state.Operation, opts ...state.Option) error {
+	options := state.NewOptions(opts...)
+	operations := make([]*pb.TransactionalStateOperation, len(ops))
+	for i := range ops {
+		op := &ops[i]
+		stateItem, err := stateItemGRPC(&op.Item, &options)
+		if err != nil {
+			return err
+		}
+		operations[i] = &pb.TransactionalStateOperation{
+			OperationType: string(op.Type),
+			Request:       stateItem,
+		}
+	}
+	if _, err := c.client.ExecuteStateTransaction(ctx, &pb.ExecuteStateTransactionRequest{
+		StoreName:  store,
+		Operations: operations,
+		Metadata:   options.Metadata,
+	}); err != nil {
+		return grpcError(err, "could not execute transaction in store %q", store)
 	}
 	return nil
 }
 
+func (c *GRPC) Query(ctx context.Context, store string, query *state.Query, opts ...state.Option) (*state.QueryResult, error) {
+	options := state.NewOptions(opts...)
+	queryBytes, err := json.Marshal(query)
+	if err != nil {
+		return nil, errorz.Internal(err, "could not serialize query")
+	}
+	resp, err := c.client.QueryStateAlpha1(ctx, &pb.QueryStateRequest{
+		StoreName: store,
+		Query:     string(queryBytes),
+		Metadata:  options.Metadata,
+	})
+	if err != nil {
+		return nil, grpcError(err, "could not query store %q", store)
+	}
+	results := make([]state.BulkItem, len(resp.Results))
+	for i, item := range resp.Results {
+		results[i] = state.BulkItem{
+			Key:   item.Key,
+			Value: item.Data,
+			ETag:  item.Etag,
+			Error: item.Error,
+		}
+	}
+	return &state.QueryResult{
+		Results:  results,
+		Token:    resp.Token,
+		Metadata: resp.Metadata,
+	}, nil
+}
+
+func (c *GRPC) PublishEvent(ctx context.Context, pubsubName string, topic string, data interface{}, opts ...pubsub.Option) error {
+	options := pubsub.NewOptions(opts...)
+	dataBytes, contentType, err := pubsub.Encode(data, options.ContentType)
+	if err != nil {
+		return errorz.Internal(err, "could not serialize event for topic %q", topic)
+	}
+	if _, err := c.client.PublishEvent(ctx, &pb.PublishEventRequest{
+		PubsubName:      pubsubName,
+		Topic:           topic,
+		Data:            dataBytes,
+		DataContentType: contentType,
+		Metadata:        options.Metadata,
+	}); err != nil {
+		return grpcError(err, "could not publish event to topic %q", topic)
+	}
+	return nil
+}
+
+func (c *GRPC) InvokeBinding(ctx context.Context, name string, req *bindings.Request) (*bindings.Response, error) {
+	data, err := req.Bytes()
+	if err != nil {
+		return nil, errorz.Internal(err, "could not serialize data for binding %q", name)
+	}
+	resp, err := c.client.InvokeBinding(ctx, &pb.InvokeBindingRequest{
+		Name:      name,
+		Data:      data,
+		Metadata:  req.Metadata,
+		Operation: req.Operation,
+	})
+	if err != nil {
+		return nil, grpcError(err, "could not invoke binding %q", name)
+	}
+	return &bindings.Response{
+		Data:     resp.Data,
+		Metadata: resp.Metadata,
+	}, nil
+}
+
 func (c *GRPC) GetSecret(ctx context.Context, store string, name string, target interface{}) error {
 	secret, err := c.client.GetSecret(ctx, &pb.GetSecretRequest{
 		StoreName: store,
 		Key:       name,
 	})
 	if err != nil {
-		return errorz.Internal(err, "could not load secret %q", name)
+		return grpcError(err, "could not load secret %q", name)
 	}
 	if secret.Data == nil {
 		return errorz.NotFound("secret %q not found", name)
@@ -110,6 +247,53 @@ func (c *GRPC) GetSecret(ctx context.Context, store string, name string, target
 	return nil
 }
 
+func stateItemGRPC(item *state.Item, options *state.Options) (*v1.StateItem, error) {
+	var data []byte
+	if item.Value != nil {
+		var err error
+		if data, err = json.Marshal(item.Value); err != nil {
+			return nil, errorz.Internal(err, "could not serialize value for key %q", item.Key)
+		}
+	}
+	return &v1.StateItem{
+		Key:      item.Key,
+		Etag:     etagGRPC(item.ETag),
+		Value:    data,
+		Metadata: options.MergeMetadata(item.Metadata),
+		Options:  stateOptionsGRPC(options),
+	}, nil
+}
+
+func stateOptionsGRPC(options *state.Options) *v1.StateOptions {
+	if options.Consistency == "" && options.Concurrency == "" {
+		return nil
+	}
+	return &v1.StateOptions{
+		Consistency: consistencyGRPC(options.Consistency),
+		Concurrency: concurrencyGRPC(options.Concurrency),
+	}
+}
+
+func consistencyGRPC(consistency state.Consistency) v1.StateOptions_StateConsistency {
+	switch consistency {
+	case state.ConsistencyEventual:
+		return v1.StateOptions_CONSISTENCY_EVENTUAL
+	case state.ConsistencyStrong:
+		return v1.StateOptions_CONSISTENCY_STRONG
+	}
+	return v1.StateOptions_CONSISTENCY_UNSPECIFIED
+}
+
+func concurrencyGRPC(concurrency state.Concurrency) v1.StateOptions_StateConcurrency {
+	switch concurrency {
+	case state.ConcurrencyFirstWrite:
+		return v1.StateOptions_CONCURRENCY_FIRST_WRITE
+	case state.ConcurrencyLastWrite:
+		return v1.StateOptions_CONCURRENCY_LAST_WRITE
+	}
+	return v1.StateOptions_CONCURRENCY_UNSPECIFIED
+}
+
 func etagGRPC(value string) *v1.Etag {
 	if value == "" {
 		return nil
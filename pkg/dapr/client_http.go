@@ -2,13 +2,21 @@ package dapr
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/url"
 	"os"
 	"path"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
 	"go.uber.org/multierr"
 
+	"github.com/pkedy/golang-dapr/pkg/components/bindings"
+	"github.com/pkedy/golang-dapr/pkg/components/pubsub"
 	"github.com/pkedy/golang-dapr/pkg/components/secrets"
 	"github.com/pkedy/golang-dapr/pkg/components/state"
 	"github.com/pkedy/golang-dapr/pkg/errorz"
@@ -16,11 +24,46 @@ import (
 
 type HTTP struct{}
 
+const metadataPrefix = "metadata."
+
+type transactionRequest struct {
+	Operations []operationHTTP   `json:"operations"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+}
+
+type operationHTTP struct {
+	Type    state.OperationType `json:"operation"`
+	Request stateItemHTTP       `json:"request"`
+}
+
+type stateItemHTTP struct {
+	state.Item
+	Options *stateOptionsHTTP `json:"options,omitempty"`
+}
+
+type stateOptionsHTTP struct {
+	Consistency state.Consistency `json:"consistency,omitempty"`
+	Concurrency state.Concurrency `json:"concurrency,omitempty"`
+}
+
+type bindingRequest struct {
+	Data      interface{}       `json:"data,omitempty"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+	Operation string            `json:"operation"`
+}
+
+type bulkStateRequest struct {
+	Keys        []string `json:"keys"`
+	Parallelism int      `json:"parallelism,omitempty"`
+}
+
 var (
 	APIURL = fmt.Sprintf("http://127.0.0.1:%s/", os.Getenv("DAPR_HTTP_PORT"))
 
 	_ = state.Store((*HTTP)(nil))
 	_ = secrets.Store((*HTTP)(nil))
+	_ = pubsub.Publisher((*HTTP)(nil))
+	_ = bindings.Invoker((*HTTP)(nil))
 )
 
 func NewHTTP(ctx context.Context) (*HTTP, error) {
@@ -31,45 +74,298 @@ func (c *HTTP) Name() string {
 	return "Custom HTTP (using Fiber client)"
 }
 
-func (c *HTTP) SetState(ctx context.Context, store string, items ...state.Item) error {
+func (c *HTTP) SetState(ctx context.Context, store string, items []state.Item, opts ...state.Option) error {
+	options := state.NewOptions(opts...)
+	stateItems := make([]stateItemHTTP, len(items))
+	for i, item := range items {
+		item.Metadata = options.MergeMetadata(item.Metadata)
+		stateItems[i] = stateItemHTTP{
+			Item:    item,
+			Options: stateOptions(&options),
+		}
+	}
 	url := APIURL + path.Join("v1.0/state", store)
 	a := fiber.Post(url)
-	defer fiber.ReleaseAgent(a)
-	code, _, errs := a.JSON(items).Bytes()
-	if code/100 != 2 {
-		return errorz.Internal(fmt.Errorf("received %d status", code), "could not save state")
+	code, body, err := send(ctx, a.JSON(stateItems))
+	if err != nil {
+		return agentError(err, "could not save state in store %q", store)
 	}
-	if len(errs) > 0 {
-		return errorz.Internal(multierr.Combine(errs...), "could not save state")
+	if code/100 != 2 {
+		return statusError(code, body, "could not save state in store %q", store)
 	}
 
 	return nil
 }
 
-func (c *HTTP) GetState(ctx context.Context, store string, key string, target interface{}) error {
+func (c *HTTP) GetState(ctx context.Context, store string, key string, target interface{}, opts ...state.Option) error {
+	_, err := c.GetStateWithETag(ctx, store, key, target, opts...)
+	return err
+}
+
+func (c *HTTP) GetStateWithETag(ctx context.Context, store string, key string, target interface{}, opts ...state.Option) (string, error) {
+	options := state.NewOptions(opts...)
 	url := APIURL + path.Join("v1.0/state", store, key)
 	a := fiber.Get(url)
-	defer fiber.ReleaseAgent(a)
-	code, _, errs := a.Struct(target)
+	a.QueryString(stateQueryString(&options))
+	resp := fiber.AcquireResponse()
+	defer fiber.ReleaseResponse(resp)
+	code, body, err := send(ctx, a.SetResponse(resp))
+	if err != nil {
+		return "", agentError(err, "could not load key %q", key)
+	}
 	if code == 204 || code == 404 {
-		return errorz.NotFound("key %q not found", key)
+		return "", errorz.NotFound("key %q not found", key)
 	}
-	if len(errs) > 0 {
-		return errorz.Internal(multierr.Combine(errs...), "could not load key %q", key)
+	if code/100 != 2 {
+		return "", statusError(code, body, "could not load key %q", key)
+	}
+	if err = json.Unmarshal(body, target); err != nil {
+		return "", errorz.Internal(err, "could decode state %q", key)
+	}
+	return string(resp.Header.Peek("ETag")), nil
+}
+
+func (c *HTTP) GetBulkState(ctx context.Context, store string, keys []string, opts ...state.Option) ([]state.BulkItem, error) {
+	options := state.NewOptions(opts...)
+	url := APIURL + path.Join("v1.0/state", store, "bulk")
+	a := fiber.Post(url)
+	a.QueryString(stateQueryString(&state.Options{Metadata: options.Metadata}))
+	code, body, err := send(ctx, a.JSON(bulkStateRequest{Keys: keys}))
+	if err != nil {
+		return nil, agentError(err, "could not load bulk state")
+	}
+	if code/100 != 2 {
+		return nil, statusError(code, body, "could not load bulk state")
+	}
+	var items []state.BulkItem
+	if err = json.Unmarshal(body, &items); err != nil {
+		return nil, errorz.Internal(err, "could decode bulk state")
+	}
+	return items, nil
+}
+
+func (c *HTTP) DeleteState(ctx context.Context, store string, key string, etag string, opts ...state.Option) error {
+	options := state.NewOptions(opts...)
+	url := APIURL + path.Join("v1.0/state", store, key)
+	a := fiber.Delete(url)
+	a.QueryString(stateQueryString(&options))
+	if etag != "" {
+		a.Set("If-Match", etag)
+	}
+	code, body, err := send(ctx, a)
+	if err != nil {
+		return agentError(err, "could not delete key %q", key)
+	}
+	if code/100 != 2 {
+		return statusError(code, body, "could not delete key %q", key)
+	}
+	return nil
+}
+
+func (c *HTTP) ExecuteStateTransaction(ctx context.Context, store string, ops []state.Operation, opts ...state.Option) error {
+	options := state.NewOptions(opts...)
+	operations := make([]operationHTTP, len(ops))
+	for i, op := range ops {
+		op.Item.Metadata = options.MergeMetadata(op.Item.Metadata)
+		operations[i] = operationHTTP{
+			Type: op.Type,
+			Request: stateItemHTTP{
+				Item:    op.Item,
+				Options: stateOptions(&options),
+			},
+		}
+	}
+	url := APIURL + path.Join("v1.0/state", store, "transaction")
+	a := fiber.Post(url)
+	code, body, err := send(ctx, a.JSON(transactionRequest{
+		Operations: operations,
+		Metadata:   options.Metadata,
+	}))
+	if err != nil {
+		return agentError(err, "could not execute transaction in store %q", store)
+	}
+	if code/100 != 2 {
+		return statusError(code, body, "could not execute transaction in store %q", store)
 	}
 	return nil
 }
 
+func (c *HTTP) Query(ctx context.Context, store string, query *state.Query, opts ...state.Option) (*state.QueryResult, error) {
+	options := state.NewOptions(opts...)
+	url := APIURL + path.Join("v1.0-alpha1/state", store, "query")
+	a := fiber.Post(url)
+	a.QueryString(stateQueryString(&state.Options{Metadata: options.Metadata}))
+	code, body, err := send(ctx, a.JSON(query))
+	if err != nil {
+		return nil, agentError(err, "could not query store %q", store)
+	}
+	if code/100 != 2 {
+		return nil, statusError(code, body, "could not query store %q", store)
+	}
+	var result state.QueryResult
+	if err = json.Unmarshal(body, &result); err != nil {
+		return nil, errorz.Internal(err, "could decode query results")
+	}
+	return &result, nil
+}
+
+func (c *HTTP) PublishEvent(ctx context.Context, pubsubName string, topic string, data interface{}, opts ...pubsub.Option) error {
+	options := pubsub.NewOptions(opts...)
+	dataBytes, contentType, err := pubsub.Encode(data, options.ContentType)
+	if err != nil {
+		return errorz.Internal(err, "could not serialize event for topic %q", topic)
+	}
+	values := url.Values{}
+	setMetadata(values, options.Metadata)
+	url := APIURL + path.Join("v1.0/publish", pubsubName, topic)
+	a := fiber.Post(url)
+	a.QueryString(values.Encode())
+	if contentType != "" {
+		a.ContentType(contentType)
+	}
+	code, body, err := send(ctx, a.Body(dataBytes))
+	if err != nil {
+		return agentError(err, "could not publish event to topic %q", topic)
+	}
+	if code/100 != 2 {
+		return statusError(code, body, "could not publish event to topic %q", topic)
+	}
+	return nil
+}
+
+func (c *HTTP) InvokeBinding(ctx context.Context, name string, req *bindings.Request) (*bindings.Response, error) {
+	// Raw bytes are sent as a JSON string instead of base64.
+	data := req.Data
+	if d, ok := data.([]byte); ok {
+		data = string(d)
+	}
+	url := APIURL + path.Join("v1.0/bindings", name)
+	a := fiber.Post(url)
+	// Metadata keys are case sensitive, so keep header names as sent.
+	if a.HostClient != nil {
+		a.HostClient.DisableHeaderNamesNormalizing = true
+	}
+	resp := fiber.AcquireResponse()
+	defer fiber.ReleaseResponse(resp)
+	code, body, err := send(ctx, a.SetResponse(resp).JSON(bindingRequest{
+		Data:      data,
+		Metadata:  req.Metadata,
+		Operation: req.Operation,
+	}))
+	if err != nil {
+		return nil, agentError(err, "could not invoke binding %q", name)
+	}
+	if code/100 != 2 {
+		return nil, statusError(code, body, "could not invoke binding %q", name)
+	}
+	return &bindings.Response{
+		Data:     body,
+		Metadata: responseMetadata(&resp.Header),
+	}, nil
+}
+
 func (c *HTTP) GetSecret(ctx context.Context, store string, name string, target interface{}) error {
 	url := APIURL + path.Join("v1.0/secrets", store, name)
 	a := fiber.Get(url)
-	defer fiber.ReleaseAgent(a)
-	code, _, errs := a.Struct(target)
+	code, body, err := send(ctx, a)
+	if err != nil {
+		return agentError(err, "could not load secret %q", name)
+	}
 	if code == 204 || code == 404 {
 		return errorz.NotFound("secret %q not found", name)
 	}
-	if len(errs) > 0 {
-		return errorz.Internal(multierr.Combine(errs...), "could not load secret %q", name)
+	if code/100 != 2 {
+		return statusError(code, body, "could not load secret %q", name)
+	}
+	if err = json.Unmarshal(body, target); err != nil {
+		return errorz.Internal(err, "could decode secret %q", name)
 	}
 	return nil
 }
+
+// send performs the request for `a`. The fiber client is not context aware,
+// so `ctx` is checked before sending and its deadline becomes the request timeout.
+// `a` is released by `Agent.Bytes`, so it must not be used or released afterwards.
+func send(ctx context.Context, a *fiber.Agent) (code int, body []byte, err error) {
+	if err = ctx.Err(); err != nil {
+		fiber.ReleaseAgent(a)
+		return 0, nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		// The deadline may pass before `ctx` is done, and fiber
+		// ignores a timeout that is not positive.
+		timeout := time.Until(deadline)
+		if timeout <= 0 {
+			fiber.ReleaseAgent(a)
+			return 0, nil, context.DeadlineExceeded
+		}
+		a.Timeout(timeout)
+	}
+	code, body, errs := a.Bytes()
+	if len(errs) > 0 {
+		if err = ctx.Err(); err != nil {
+			return 0, nil, err
+		}
+		for _, err := range errs {
+			if errors.Is(err, fasthttp.ErrTimeout) {
+				return 0, nil, context.DeadlineExceeded
+			}
+		}
+		return 0, nil, multierr.Combine(errs...)
+	}
+	return code, body, nil
+}
+
+func agentError(err error, format string, args ...interface{}) *errorz.Error {
+	if ctxErr := contextError(err, format, args...); ctxErr != nil {
+		return ctxErr
+	}
+	return errorz.Internal(err, format, args...)
+}
+
+func stateOptions(options *state.Options) *stateOptionsHTTP {
+	if options.Consistency == "" && options.Concurrency == "" {
+		return nil
+	}
+	return &stateOptionsHTTP{
+		Consistency: options.Consistency,
+		Concurrency: options.Concurrency,
+	}
+}
+
+// stateQueryString encodes options as query parameters with metadata prefixed by "metadata.".
+func stateQueryString(options *state.Options) string {
+	values := url.Values{}
+	if options.Consistency != "" {
+		values.Set("consistency", string(options.Consistency))
+	}
+	if options.Concurrency != "" {
+		values.Set("concurrency", string(options.Concurrency))
+	}
+	setMetadata(values, options.Metadata)
+	return values.Encode()
+}
+
+// setMetadata adds `metadata` to query parameters with the "metadata." prefix Dapr expects.
+func setMetadata(values url.Values, metadata map[string]string) {
+	for k, v := range metadata {
+		values.Set(metadataPrefix+k, v)
+	}
+}
+
+// responseMetadata returns the headers with the "metadata." prefix Dapr
+// uses for response metadata, or nil if there are none.
+func responseMetadata(header *fasthttp.ResponseHeader) map[string]string {
+	var metadata map[string]string
+	header.VisitAll(func(key, value []byte) {
+		k := string(key)
+		if len(k) <= len(metadataPrefix) || !strings.EqualFold(k[:len(metadataPrefix)], metadataPrefix) {
+			return
+		}
+		if metadata == nil {
+			metadata = make(map[string]string)
+		}
+		metadata[k[len(metadataPrefix):]] = string(value)
+	})
+	return metadata
+}
@@ -6,6 +6,8 @@ import (
 
 	dapr "github.com/dapr/go-sdk/client"
 
+	"github.com/pkedy/golang-dapr/pkg/components/bindings"
+	"github.com/pkedy/golang-dapr/pkg/components/pubsub"
 	"github.com/pkedy/golang-dapr/pkg/components/secrets"
 	"github.com/pkedy/golang-dapr/pkg/components/state"
 	"github.com/pkedy/golang-dapr/pkg/errorz"
@@ -18,6 +20,8 @@ type Client struct {
 var (
 	_ = state.Store((*Client)(nil))
 	_ = secrets.Store((*Client)(nil))
+	_ = pubsub.Publisher((*Client)(nil))
+	_ = bindings.Invoker((*Client)(nil))
 )
 
 func NewSDK(ctx context.Context) (*Client, error) {
@@ -34,45 +38,162 @@ func (c *Client) Name() string {
 	return "Go SDK"
 }
 
-func (c *Client) SetState(ctx context.Context, store string, items ...state.Item) error {
+func (c *Client) SetState(ctx context.Context, store string, items []state.Item, opts ...state.Option) error {
+	options := state.NewOptions(opts...)
 	stateItems := make([]*dapr.SetStateItem, len(items))
 	for i := range items {
-		item := items[i]
-		data, err := json.Marshal(item.Value)
+		stateItem, err := setStateItem(&items[i], &options)
 		if err != nil {
-			return errorz.Internal(err, "could not serialize value for key %q", item.Key)
-		}
-		stateItems[i] = &dapr.SetStateItem{
-			Key:   item.Key,
-			Etag:  etag(item.ETag),
-			Value: data,
+			return err
 		}
+		stateItems[i] = stateItem
 	}
 	if err := c.client.SaveBulkState(ctx, store, stateItems...); err != nil {
-		return errorz.Internal(err, "could not save state in store %q", store)
+		return grpcError(err, "could not save state in store %q", store)
 	}
 
 	return nil
 }
 
-func (c *Client) GetState(ctx context.Context, store string, key string, target interface{}) error {
-	state, err := c.client.GetState(ctx, store, key)
+func (c *Client) GetState(ctx context.Context, store string, key string, target interface{}, opts ...state.Option) error {
+	_, err := c.GetStateWithETag(ctx, store, key, target, opts...)
+	return err
+}
+
+func (c *Client) GetStateWithETag(ctx context.Context, store string, key string, target interface{}, opts ...state.Option) (string, error) {
+	options := state.NewOptions(opts...)
+	state, err := c.client.GetStateWithConsistency(ctx, store, key,
+		options.Metadata, stateOptionsSDK(&options).Consistency)
 	if err != nil {
-		return errorz.Internal(err, "could not load state %q", key)
+		return "", grpcError(err, "could not load state %q", key)
 	}
 	if state.Value == nil {
-		return errorz.NotFound("key %q not found", key)
+		return "", errorz.NotFound("key %q not found", key)
 	}
 	if err = json.Unmarshal(state.Value, target); err != nil {
-		return errorz.Internal(err, "could decode state %q", key)
+		return "", errorz.Internal(err, "could decode state %q", key)
+	}
+	return state.Etag, nil
+}
+
+func (c *Client) GetBulkState(ctx context.Context, store string, keys []string, opts ...state.Option) ([]state.BulkItem, error) {
+	options := state.NewOptions(opts...)
+	bulkItems, err := c.client.GetBulkState(ctx, store, keys, options.Metadata, 0)
+	if err != nil {
+		return nil, grpcError(err, "could not load bulk state")
+	}
+	items := make([]state.BulkItem, len(bulkItems))
+	for i, item := range bulkItems {
+		items[i] = state.BulkItem{
+			Key:   item.Key,
+			Value: item.Value,
+			ETag:  item.Etag,
+			Error: item.Error,
+		}
+	}
+	return items, nil
+}
+
+func (c *Client) DeleteState(ctx context.Context, store string, key string, etagValue string, opts ...state.Option) error {
+	options := state.NewOptions(opts...)
+	if err := c.client.DeleteStateWithETag(ctx, store, key, etag(etagValue),
+		options.Metadata, stateOptionsSDK(&options)); err != nil {
+		return grpcError(err, "could not delete key %q", key)
+	}
+	return nil
+}
+
+func (c *Client) ExecuteStateTransaction(ctx context.Context, store string, ops []state.Operation, opts ...state.Option) error {
+	options := state.NewOptions(opts...)
+	operations := make([]*dapr.StateOperation, len(ops))
+	for i := range ops {
+		op := &ops[i]
+		stateItem, err := setStateItem(&op.Item, &options)
+		if err != nil {
+			return err
+		}
+		opType := dapr.StateOperationTypeUpsert
+		if op.Type == state.OperationDelete {
+			opType = dapr.StateOperationTypeDelete
+		}
+		operations[i] = &dapr.StateOperation{
+			Type: opType,
+			Item: stateItem,
+		}
+	}
+	if err := c.client.ExecuteStateTransaction(ctx, store, options.Metadata, operations); err != nil {
+		return grpcError(err, "could not execute transaction in store %q", store)
+	}
+	return nil
+}
+
+func (c *Client) Query(ctx context.Context, store string, query *state.Query, opts ...state.Option) (*state.QueryResult, error) {
+	options := state.NewOptions(opts...)
+	queryBytes, err := json.Marshal(query)
+	if err != nil {
+		return nil, errorz.Internal(err, "could not serialize query")
+	}
+	resp, err := c.client.QueryStateAlpha1(ctx, store, string(queryBytes), options.Metadata)
+	if err != nil {
+		return nil, grpcError(err, "could not query store %q", store)
+	}
+	results := make([]state.BulkItem, len(resp.Results))
+	for i, item := range resp.Results {
+		results[i] = state.BulkItem{
+			Key:   item.Key,
+			Value: item.Value,
+			ETag:  item.Etag,
+			Error: item.Error,
+		}
+	}
+	return &state.QueryResult{
+		Results:  results,
+		Token:    resp.Token,
+		Metadata: resp.Metadata,
+	}, nil
+}
+
+func (c *Client) PublishEvent(ctx context.Context, pubsubName string, topic string, data interface{}, opts ...pubsub.Option) error {
+	options := pubsub.NewOptions(opts...)
+	dataBytes, contentType, err := pubsub.Encode(data, options.ContentType)
+	if err != nil {
+		return errorz.Internal(err, "could not serialize event for topic %q", topic)
+	}
+	if err := c.client.PublishEvent(ctx, pubsubName, topic, dataBytes,
+		dapr.PublishEventWithContentType(contentType),
+		dapr.PublishEventWithMetadata(options.Metadata)); err != nil {
+		return grpcError(err, "could not publish event to topic %q", topic)
 	}
 	return nil
 }
 
+func (c *Client) InvokeBinding(ctx context.Context, name string, req *bindings.Request) (*bindings.Response, error) {
+	data, err := req.Bytes()
+	if err != nil {
+		return nil, errorz.Internal(err, "could not serialize data for binding %q", name)
+	}
+	event, err := c.client.InvokeBinding(ctx, &dapr.InvokeBindingRequest{
+		Name:      name,
+		Operation: req.Operation,
+		Data:      data,
+		Metadata:  req.Metadata,
+	})
+	if err != nil {
+		return nil, grpcError(err, "could not invoke binding %q", name)
+	}
+	if event == nil {
+		return &bindings.Response{}, nil
+	}
+	return &bindings.Response{
+		Data:     event.Data,
+		Metadata: event.Metadata,
+	}, nil
+}
+
 func (c *Client) GetSecret(ctx context.Context, store string, name string, target interface{}) error {
 	secret, err := c.client.GetSecret(ctx, store, name, nil)
 	if err != nil {
-		return errorz.Internal(err, "could not load secret %q", name)
+		return grpcError(err, "could not load secret %q", name)
 	}
 	if secret == nil {
 		return errorz.NotFound("secret %q not found", name)
@@ -88,6 +209,42 @@ func (c *Client) GetSecret(ctx context.Context, store string, name string, targe
 	return nil
 }
 
+func setStateItem(item *state.Item, options *state.Options) (*dapr.SetStateItem, error) {
+	var data []byte
+	if item.Value != nil {
+		var err error
+		if data, err = json.Marshal(item.Value); err != nil {
+			return nil, errorz.Internal(err, "could not serialize value for key %q", item.Key)
+		}
+	}
+	return &dapr.SetStateItem{
+		Key:      item.Key,
+		Etag:     etag(item.ETag),
+		Value:    data,
+		Metadata: options.MergeMetadata(item.Metadata),
+		Options:  stateOptionsSDK(options),
+	}, nil
+}
+
+// stateOptionsSDK always returns options, because the SDK substitutes
+// its own defaults when they are nil.
+func stateOptionsSDK(options *state.Options) *dapr.StateOptions {
+	var so dapr.StateOptions
+	switch options.Consistency {
+	case state.ConsistencyEventual:
+		so.Consistency = dapr.StateConsistencyEventual
+	case state.ConsistencyStrong:
+		so.Consistency = dapr.StateConsistencyStrong
+	}
+	switch options.Concurrency {
+	case state.ConcurrencyFirstWrite:
+		so.Concurrency = dapr.StateConcurrencyFirstWrite
+	case state.ConcurrencyLastWrite:
+		so.Concurrency = dapr.StateConcurrencyLastWrite
+	}
+	return &so
+}
+
 func etag(value string) *dapr.ETag {
 	if value == "" {
 		return nil
@@ -0,0 +1,272 @@
+package dapr
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	pb "github.com/dapr/dapr/pkg/proto/runtime/v1"
+	sdk "github.com/dapr/go-sdk/client"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/pkedy/golang-dapr/pkg/components/bindings"
+	"github.com/pkedy/golang-dapr/pkg/components/pubsub"
+	"github.com/pkedy/golang-dapr/pkg/components/secrets"
+	"github.com/pkedy/golang-dapr/pkg/components/state"
+	"github.com/pkedy/golang-dapr/pkg/errorz"
+)
+
+type testClient interface {
+	Name() string
+	state.Store
+	secrets.Store
+	pubsub.Publisher
+	bindings.Invoker
+}
+
+// fakeDapr is a Dapr sidecar that fails every request with `err`.
+// If `wait` is set, it instead waits for the request's context to end.
+type fakeDapr struct {
+	pb.UnimplementedDaprServer
+	err  error
+	wait bool
+}
+
+func (f *fakeDapr) fail(ctx context.Context) error {
+	if f.wait {
+		<-ctx.Done()
+		return status.FromContextError(ctx.Err()).Err()
+	}
+	return f.err
+}
+
+func (f *fakeDapr) SaveState(ctx context.Context, _ *pb.SaveStateRequest) (*emptypb.Empty, error) {
+	return nil, f.fail(ctx)
+}
+
+func (f *fakeDapr) GetState(ctx context.Context, _ *pb.GetStateRequest) (*pb.GetStateResponse, error) {
+	return nil, f.fail(ctx)
+}
+
+func (f *fakeDapr) GetBulkState(ctx context.Context, _ *pb.GetBulkStateRequest) (*pb.GetBulkStateResponse, error) {
+	return nil, f.fail(ctx)
+}
+
+func (f *fakeDapr) DeleteState(ctx context.Context, _ *pb.DeleteStateRequest) (*emptypb.Empty, error) {
+	return nil, f.fail(ctx)
+}
+
+func (f *fakeDapr) ExecuteStateTransaction(ctx context.Context, _ *pb.ExecuteStateTransactionRequest) (*emptypb.Empty, error) {
+	return nil, f.fail(ctx)
+}
+
+func (f *fakeDapr) QueryStateAlpha1(ctx context.Context, _ *pb.QueryStateRequest) (*pb.QueryStateResponse, error) {
+	return nil, f.fail(ctx)
+}
+
+func (f *fakeDapr) PublishEvent(ctx context.Context, _ *pb.PublishEventRequest) (*emptypb.Empty, error) {
+	return nil, f.fail(ctx)
+}
+
+func (f *fakeDapr) InvokeBinding(ctx context.Context, _ *pb.InvokeBindingRequest) (*pb.InvokeBindingResponse, error) {
+	return nil, f.fail(ctx)
+}
+
+func (f *fakeDapr) GetSecret(ctx context.Context, _ *pb.GetSecretRequest) (*pb.GetSecretResponse, error) {
+	return nil, f.fail(ctx)
+}
+
+// grpcClients returns the gRPC and SDK clients connected to `fake`.
+func grpcClients(t *testing.T, fake *fakeDapr) []testClient {
+	t.Helper()
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	pb.RegisterDaprServer(server, fake)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return []testClient{
+		&GRPC{client: pb.NewDaprClient(conn)},
+		&Client{client: sdk.NewClientWithConnection(conn)},
+	}
+}
+
+// httpClient returns the HTTP client connected to a fake sidecar
+// that responds to every request with `code` after `delay`.
+func httpClient(t *testing.T, code int, delay time.Duration) testClient {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		w.WriteHeader(code)
+		w.Write([]byte(`{"errorCode":"ERR_TEST","message":"test"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	apiURL := APIURL
+	APIURL = server.URL + "/"
+	t.Cleanup(func() { APIURL = apiURL })
+
+	return &HTTP{}
+}
+
+func TestClientErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		grpcCode codes.Code
+		httpCode int
+		errType  string
+	}{
+		{"conflict", codes.Aborted, http.StatusConflict, errorz.TypeConflict},
+		{"not found", codes.NotFound, http.StatusNotFound, errorz.TypeNotFound},
+		{"unavailable", codes.Unavailable, http.StatusServiceUnavailable, errorz.TypeUnavailable},
+		{"internal", codes.Internal, http.StatusInternalServerError, errorz.TypeInternal},
+		{"invalid argument", codes.InvalidArgument, http.StatusBadRequest, errorz.TypeInvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clients := grpcClients(t, &fakeDapr{err: status.Error(tt.grpcCode, "test")})
+			if tt.grpcCode != codes.Unavailable {
+				// The HTTP sidecar API has no unavailable status.
+				clients = append(clients, httpClient(t, tt.httpCode, 0))
+			}
+			for _, c := range clients {
+				assertClientErrors(t, c, context.Background(), tt.errType)
+			}
+		})
+	}
+}
+
+func TestClientDeadlineExceeded(t *testing.T) {
+	t.Run("expired", func(t *testing.T) {
+		clients := grpcClients(t, &fakeDapr{})
+		clients = append(clients, httpClient(t, http.StatusOK, 0))
+		ctx, cancel := context.WithTimeout(context.Background(), -time.Second)
+		defer cancel()
+		for _, c := range clients {
+			assertClientErrors(t, c, ctx, errorz.TypeDeadlineExceeded)
+		}
+	})
+	t.Run("in flight", func(t *testing.T) {
+		clients := grpcClients(t, &fakeDapr{wait: true})
+		clients = append(clients, httpClient(t, http.StatusOK, 500*time.Millisecond))
+		for _, c := range clients {
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			assertClientErrors(t, c, ctx, errorz.TypeDeadlineExceeded)
+			cancel()
+		}
+	})
+}
+
+func TestClientCanceled(t *testing.T) {
+	t.Run("canceled", func(t *testing.T) {
+		clients := grpcClients(t, &fakeDapr{})
+		clients = append(clients, httpClient(t, http.StatusOK, 0))
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		for _, c := range clients {
+			assertClientErrors(t, c, ctx, errorz.TypeCanceled)
+		}
+	})
+	t.Run("in flight", func(t *testing.T) {
+		// The fiber client cannot abort a request, so only the gRPC clients are tested.
+		for _, c := range grpcClients(t, &fakeDapr{wait: true}) {
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(50*time.Millisecond, cancel)
+			assertClientErrors(t, c, ctx, errorz.TypeCanceled)
+		}
+	})
+}
+
+func assertClientErrors(t *testing.T, c testClient, ctx context.Context, errType string) {
+	t.Helper()
+	var target map[string]string
+	calls := map[string]func() error{
+		"SetState": func() error {
+			return c.SetState(ctx, "statestore", []state.Item{{Key: "key", Value: "value"}})
+		},
+		"GetState": func() error {
+			return c.GetState(ctx, "statestore", "key", &target)
+		},
+		"GetStateWithETag": func() error {
+			_, err := c.GetStateWithETag(ctx, "statestore", "key", &target)
+			return err
+		},
+		"GetBulkState": func() error {
+			_, err := c.GetBulkState(ctx, "statestore", []string{"key"})
+			return err
+		},
+		"DeleteState": func() error {
+			return c.DeleteState(ctx, "statestore", "key", "1")
+		},
+		"ExecuteStateTransaction": func() error {
+			return c.ExecuteStateTransaction(ctx, "statestore", []state.Operation{
+				state.Upsert(state.Item{Key: "key", Value: "value"}),
+			})
+		},
+		"Query": func() error {
+			_, err := c.Query(ctx, "statestore", state.NewQuery(state.Eq("type", "test")))
+			return err
+		},
+		"PublishEvent": func() error {
+			return c.PublishEvent(ctx, "pubsub", "topic", map[string]string{"key": "value"})
+		},
+		"InvokeBinding": func() error {
+			_, err := c.InvokeBinding(ctx, "binding", &bindings.Request{Operation: bindings.OperationCreate})
+			return err
+		},
+		"GetSecret": func() error {
+			return c.GetSecret(ctx, "secretstore", "name", &target)
+		},
+	}
+	for name, call := range calls {
+		err := call()
+		if err == nil {
+			t.Errorf("%s: %s: expected an error", c.Name(), name)
+			continue
+		}
+		if got := errorz.From(err).Type; got != errType {
+			t.Errorf("%s: %s: expected %s, got %s (%v)", c.Name(), name, errType, got, err)
+		}
+	}
+}
+
+func TestHTTPInvokeBindingMetadata(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Set directly so the header name is not canonicalized.
+		w.Header()["metadata.blobName"] = []string{"blob-1"}
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+	apiURL := APIURL
+	APIURL = server.URL + "/"
+	defer func() { APIURL = apiURL }()
+
+	resp, err := (&HTTP{}).InvokeBinding(context.Background(), "storage", &bindings.Request{
+		Operation: bindings.OperationCreate,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := resp.Metadata["blobName"]; got != "blob-1" {
+		t.Errorf("expected blobName metadata blob-1, got %q (%v)", got, resp.Metadata)
+	}
+}
@@ -1,10 +1,37 @@
 package dapr
 
 import (
+	"context"
+	"crypto/rand"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
 	"time"
+
+	"go.uber.org/multierr"
+)
+
+// Extension attributes that Dapr adds to events.
+const (
+	ExtensionPubsubName  = "pubsubname"
+	ExtensionTopic       = "topic"
+	ExtensionTraceID     = "traceid"
+	ExtensionTraceParent = "traceparent"
+	ExtensionTraceState  = "tracestate"
+)
+
+const (
+	specVersion = "1.0"
+
+	// Prefix of CloudEvent attribute headers in binary mode.
+	headerPrefix = "ce-"
 )
 
+var ErrInvalidCloudEvent = errors.New("invalid CloudEvent")
+
 // CloudEvents specification can be found at
 // https://github.com/cloudevents/spec/blob/v1.0.1/spec.md
 type CloudEvent struct {
@@ -16,5 +43,296 @@ type CloudEvent struct {
 	DataSchema      string          `json:"dataschema,omitempty"`
 	Subject         string          `json:"subject,omitempty"`
 	Time            time.Time       `json:"time,omitempty"`
-	Data            json.RawMessage `json:"data"`
+	Data            json.RawMessage `json:"data,omitempty"`
+	DataBase64      []byte          `json:"data_base64,omitempty"`
+	// Extensions holds all other attributes, including
+	// the ones Dapr adds, i.e. `ExtensionTraceParent`.
+	Extensions map[string]interface{} `json:"-"`
+}
+
+// cloudEvent has the fields of CloudEvent without its JSON methods.
+type cloudEvent CloudEvent
+
+// contextKey is the context key for the event being handled.
+type contextKey struct{}
+
+// Attributes of the CloudEvent struct. Everything else is an extension.
+var contextAttributes = map[string]struct{}{
+	"id":              {},
+	"source":          {},
+	"specversion":     {},
+	"type":            {},
+	"datacontenttype": {},
+	"dataschema":      {},
+	"subject":         {},
+	"time":            {},
+	"data":            {},
+	"data_base64":     {},
+}
+
+// Extension names are lowercase letters and digits.
+func validExtensionName(name string) bool {
+	if name == "" {
+		return false
+	}
+	if _, ok := contextAttributes[name]; ok {
+		return false
+	}
+	for _, r := range name {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// Bytes returns the event data. Binary data (`data_base64`) is returned decoded
+// and non-JSON data sent as a JSON string (e.g. text/plain) is returned unquoted.
+func (e *CloudEvent) Bytes() []byte {
+	if e.DataBase64 != nil {
+		return e.DataBase64
+	}
+	if !isJSON(e.DataContentType) && len(e.Data) > 0 && e.Data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(e.Data, &s); err == nil {
+			return []byte(s)
+		}
+	}
+	return e.Data
+}
+
+// DecodeData decodes the event data into `target` based on `DataContentType`.
+func (e *CloudEvent) DecodeData(target interface{}) error {
+	return DecodeData(e.DataContentType, e.Bytes(), target)
+}
+
+// setData sets `Data` for JSON content and `DataBase64` for anything else.
+func (e *CloudEvent) setData(data []byte) {
+	if isJSON(e.DataContentType) && json.Valid(data) {
+		e.Data = data
+	} else {
+		e.DataBase64 = data
+	}
+}
+
+// Extension returns the extension attribute `name` as a string.
+func (e *CloudEvent) Extension(name string) string {
+	switch v := e.Extensions[name].(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// SetExtension sets the extension attribute `name`.
+// Empty values remove the extension.
+func (e *CloudEvent) SetExtension(name string, value interface{}) {
+	if value == nil || value == "" {
+		delete(e.Extensions, name)
+		return
+	}
+	if e.Extensions == nil {
+		e.Extensions = make(map[string]interface{})
+	}
+	e.Extensions[name] = value
+}
+
+// Validate checks the required attributes and extension names.
+func (e *CloudEvent) Validate() error {
+	var errs error
+	required := func(name, value string) {
+		if value == "" {
+			errs = multierr.Append(errs, fmt.Errorf("%w: %s is required", ErrInvalidCloudEvent, name))
+		}
+	}
+	required("id", e.ID)
+	required("source", e.Source)
+	required("type", e.Type)
+	if e.SpecVersion != specVersion {
+		errs = multierr.Append(errs, fmt.Errorf("%w: unsupported specversion %q", ErrInvalidCloudEvent, e.SpecVersion))
+	}
+	if len(e.Data) > 0 && len(e.DataBase64) > 0 {
+		errs = multierr.Append(errs, fmt.Errorf("%w: data and data_base64 are mutually exclusive", ErrInvalidCloudEvent))
+	}
+	for name := range e.Extensions {
+		if !validExtensionName(name) {
+			errs = multierr.Append(errs, fmt.Errorf("%w: invalid extension name %q", ErrInvalidCloudEvent, name))
+		}
+	}
+	return errs
+}
+
+// MarshalJSON encodes the event in structured mode,
+// with extensions as top-level attributes.
+func (e CloudEvent) MarshalJSON() ([]byte, error) {
+	attributes := make(map[string]interface{}, len(e.Extensions)+10)
+	for name, value := range e.Extensions {
+		attributes[name] = value
+	}
+	attributes["id"] = e.ID
+	attributes["source"] = e.Source
+	attributes["specversion"] = e.SpecVersion
+	attributes["type"] = e.Type
+	optional := func(name, value string) {
+		if value != "" {
+			attributes[name] = value
+		}
+	}
+	optional("datacontenttype", e.DataContentType)
+	optional("dataschema", e.DataSchema)
+	optional("subject", e.Subject)
+	if !e.Time.IsZero() {
+		attributes["time"] = e.Time.Format(time.RFC3339Nano)
+	}
+	if len(e.Data) > 0 {
+		attributes["data"] = e.Data
+	}
+	if len(e.DataBase64) > 0 {
+		attributes["data_base64"] = e.DataBase64
+	}
+	return json.Marshal(attributes)
+}
+
+// UnmarshalJSON decodes a structured mode event.
+// Unknown attributes are decoded into `Extensions`.
+func (e *CloudEvent) UnmarshalJSON(data []byte) error {
+	var event cloudEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return err
+	}
+	var attributes map[string]json.RawMessage
+	if err := json.Unmarshal(data, &attributes); err != nil {
+		return err
+	}
+	for name, raw := range attributes {
+		if _, ok := contextAttributes[name]; ok {
+			continue
+		}
+		var value interface{}
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return err
+		}
+		if event.Extensions == nil {
+			event.Extensions = make(map[string]interface{}, len(attributes))
+		}
+		event.Extensions[name] = value
+	}
+	*e = CloudEvent(event)
+	return nil
+}
+
+// BinaryHeaders returns the HTTP headers for sending the event in binary mode,
+// where the body is `Bytes()`. Header values are percent-encoded.
+func (e *CloudEvent) BinaryHeaders() map[string]string {
+	headers := make(map[string]string, len(e.Extensions)+8)
+	set := func(name, value string) {
+		if value != "" {
+			headers[headerPrefix+name] = url.PathEscape(value)
+		}
+	}
+	set("id", e.ID)
+	set("source", e.Source)
+	set("specversion", e.SpecVersion)
+	set("type", e.Type)
+	set("dataschema", e.DataSchema)
+	set("subject", e.Subject)
+	if !e.Time.IsZero() {
+		set("time", e.Time.Format(time.RFC3339Nano))
+	}
+	names := make([]string, 0, len(e.Extensions))
+	for name := range e.Extensions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		set(name, e.Extension(name))
+	}
+	if e.DataContentType != "" {
+		headers["Content-Type"] = e.DataContentType
+	}
+	return headers
+}
+
+// setBinaryHeader sets the attribute for a binary mode header.
+// It ignores headers that are not CloudEvent attributes.
+func (e *CloudEvent) setBinaryHeader(key, value string) error {
+	key = strings.ToLower(key)
+	if !strings.HasPrefix(key, headerPrefix) {
+		return nil
+	}
+	name := strings.TrimPrefix(key, headerPrefix)
+	if v, err := url.PathUnescape(value); err == nil {
+		value = v
+	}
+	switch name {
+	case "id":
+		e.ID = value
+	case "source":
+		e.Source = value
+	case "specversion":
+		e.SpecVersion = value
+	case "type":
+		e.Type = value
+	case "dataschema":
+		e.DataSchema = value
+	case "subject":
+		e.Subject = value
+	case "time":
+		t, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return fmt.Errorf("%w: invalid time %q", ErrInvalidCloudEvent, value)
+		}
+		e.Time = t
+	default:
+		e.SetExtension(name, value)
+	}
+	return nil
+}
+
+// WithEvent returns a context that carries the event being handled.
+func WithEvent(ctx context.Context, event *CloudEvent) context.Context {
+	return context.WithValue(ctx, contextKey{}, event)
+}
+
+// EventFromContext returns the event being handled, which
+// gives event handlers access to all of its attributes.
+func EventFromContext(ctx context.Context) (*CloudEvent, bool) {
+	event, ok := ctx.Value(contextKey{}).(*CloudEvent)
+	return event, ok
+}
+
+// NewCloudEvent creates a CloudEvent with a random ID and `data` serialized as JSON.
+// Publish it with `pubsub.WithCloudEvent()` so Dapr does not wrap it in another envelope.
+func NewCloudEvent(source, eventType string, data interface{}) (*CloudEvent, error) {
+	dataBytes, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	return &CloudEvent{
+		ID:              id,
+		Source:          source,
+		SpecVersion:     specVersion,
+		Type:            eventType,
+		DataContentType: "application/json",
+		Time:            time.Now().UTC(),
+		Data:            dataBytes,
+	}, nil
+}
+
+// newID returns a random (version 4) UUID.
+func newID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
 }
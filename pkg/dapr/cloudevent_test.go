@@ -0,0 +1,132 @@
+package dapr
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// parseRequest runs `ParseCloudEvent` for a request with `headers` and `body`.
+func parseRequest(t *testing.T, headers map[string]string, body string) (*CloudEvent, error) {
+	t.Helper()
+	var (
+		event *CloudEvent
+		err   error
+	)
+	app := fiber.New()
+	app.Post("/", func(c *fiber.Ctx) error {
+		event, err = ParseCloudEvent(c)
+		return nil
+	})
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	if _, testErr := app.Test(req); testErr != nil {
+		t.Fatal(testErr)
+	}
+	return event, err
+}
+
+func binaryHeaders(contentType string) map[string]string {
+	return map[string]string{
+		"ce-specversion": "1.0",
+		"ce-id":          "1",
+		"ce-source":      "inventory",
+		"ce-type":        "widget.saved.v1",
+		"ce-time":        "2022-07-01T12:00:00Z",
+		"ce-tenant":      "acme%20corp",
+		"Content-Type":   contentType,
+	}
+}
+
+func TestParseCloudEventBinary(t *testing.T) {
+	event, err := parseRequest(t, binaryHeaders("text/plain"), "hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.ID != "1" || event.Source != "inventory" || event.Type != "widget.saved.v1" {
+		t.Errorf("unexpected attributes: %+v", event)
+	}
+	if want := time.Date(2022, 7, 1, 12, 0, 0, 0, time.UTC); !event.Time.Equal(want) {
+		t.Errorf("expected time %v, got %v", want, event.Time)
+	}
+	if tenant := event.Extension("tenant"); tenant != "acme corp" {
+		t.Errorf("expected the decoded tenant extension, got %q", tenant)
+	}
+	if event.DataContentType != "text/plain" || event.Data != nil || string(event.Bytes()) != "hello" {
+		t.Errorf("expected text data in data_base64, got %q and %q", event.Data, event.DataBase64)
+	}
+}
+
+func TestParseCloudEventBinaryJSON(t *testing.T) {
+	event, err := parseRequest(t, binaryHeaders("application/json"), `{"id":"w1"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(event.Data) != `{"id":"w1"}` || event.DataBase64 != nil {
+		t.Errorf("expected JSON data, got %q and %q", event.Data, event.DataBase64)
+	}
+	var widget struct{ ID string }
+	if err := event.DecodeData(&widget); err != nil || widget.ID != "w1" {
+		t.Errorf("expected to decode the widget, got %+v (%v)", widget, err)
+	}
+}
+
+func TestParseCloudEventStructured(t *testing.T) {
+	event, err := parseRequest(t, map[string]string{"Content-Type": "application/cloudevents+json"},
+		`{"specversion":"1.0","id":"1","source":"inventory","type":"widget.saved.v1","tenant":"acme","data":{"id":"w1"}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.Extension("tenant") != "acme" || string(event.Data) != `{"id":"w1"}` {
+		t.Errorf("unexpected event: %+v", event)
+	}
+}
+
+func TestParseCloudEventInvalid(t *testing.T) {
+	missingID := binaryHeaders("text/plain")
+	delete(missingID, "ce-id")
+	badTime := binaryHeaders("text/plain")
+	badTime["ce-time"] = "yesterday"
+	badExtension := binaryHeaders("text/plain")
+	badExtension["ce-tenant_id"] = "acme"
+	tests := []struct {
+		name    string
+		headers map[string]string
+		body    string
+	}{
+		{"binary missing id", missingID, "hello"},
+		{"binary invalid time", badTime, "hello"},
+		{"binary invalid extension", badExtension, "hello"},
+		{"structured not JSON", nil, "hello"},
+		{"structured wrong specversion", nil, `{"specversion":"0.3","id":"1","source":"inventory","type":"widget"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseRequest(t, tt.headers, tt.body); !errors.Is(err, ErrInvalidCloudEvent) {
+				t.Errorf("expected %v, got %v", ErrInvalidCloudEvent, err)
+			}
+		})
+	}
+}
+
+func TestBinaryHeadersRoundTrip(t *testing.T) {
+	event, err := parseRequest(t, binaryHeaders("text/plain"), "hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := parseRequest(t, event.BinaryHeaders(), string(event.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.ID != event.ID || !parsed.Time.Equal(event.Time) ||
+		parsed.Extension("tenant") != "acme corp" || string(parsed.Bytes()) != "hello" {
+		t.Errorf("expected %+v, got %+v", event, parsed)
+	}
+}
@@ -0,0 +1,61 @@
+package dapr
+
+import (
+	"encoding/json"
+	"mime"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	contentTypeProtobuf  = "application/protobuf"
+	contentTypeXProtobuf = "application/x-protobuf"
+)
+
+// DecodeData decodes event `data` into `target` based on its content type.
+//
+//   - `*[]byte` and `*string` targets receive the data as is, for formats
+//     such as Avro that the caller decodes itself.
+//   - `proto.Message` targets are decoded from protobuf content types.
+//   - Everything else is decoded as JSON. This includes raw payloads
+//     (application/octet-stream) which are usually JSON without an envelope.
+func DecodeData(contentType string, data []byte, target interface{}) error {
+	switch t := target.(type) {
+	case *[]byte:
+		*t = data
+		return nil
+	case *string:
+		*t = string(data)
+		return nil
+	case proto.Message:
+		if isProtobuf(contentType) {
+			return proto.Unmarshal(data, t)
+		}
+	}
+	return json.Unmarshal(data, target)
+}
+
+func isJSON(contentType string) bool {
+	mediaType := parseMediaType(contentType)
+	return mediaType == "" ||
+		mediaType == "application/json" ||
+		mediaType == "text/json" ||
+		strings.HasSuffix(mediaType, "+json")
+}
+
+func isProtobuf(contentType string) bool {
+	mediaType := parseMediaType(contentType)
+	return mediaType == contentTypeProtobuf || mediaType == contentTypeXProtobuf
+}
+
+func parseMediaType(contentType string) string {
+	if contentType == "" {
+		return ""
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return strings.ToLower(contentType)
+	}
+	return mediaType
+}
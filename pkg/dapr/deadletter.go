@@ -0,0 +1,73 @@
+package dapr
+
+import (
+	"context"
+
+	pb "github.com/dapr/dapr/pkg/proto/runtime/v1"
+	"github.com/dapr/go-sdk/service/common"
+	"github.com/gofiber/fiber/v2"
+)
+
+// DeadLetterHandler consumes a dead-letter topic. Dapr delivers dead-lettered
+// messages with the original CloudEvent intact, so the handler receives
+// the whole event to inspect or re-drive it.
+type DeadLetterHandler struct {
+	PubsubName string
+	Topic      string
+	Route      string
+	Handle     func(ctx context.Context, event *CloudEvent) error
+}
+
+// HandleDeadLetter creates a handler for all events on the dead-letter `topic`.
+func HandleDeadLetter(pubsubName, topic, route string, handle func(ctx context.Context, event *CloudEvent) error) *DeadLetterHandler {
+	return &DeadLetterHandler{
+		PubsubName: pubsubName,
+		Topic:      topic,
+		Route:      route,
+		Handle:     handle,
+	}
+}
+
+func (h *DeadLetterHandler) Subscription() Subscription {
+	return Subscription{
+		PubsubName: h.PubsubName,
+		Topic:      h.Topic,
+		Routes: Routes{
+			Default: h.Route,
+		},
+	}
+}
+
+func (h *DeadLetterHandler) Path() string {
+	return h.Route
+}
+
+func (h *DeadLetterHandler) HandleHTTP(c *fiber.Ctx) error {
+	event, err := ParseCloudEvent(c)
+	if err != nil {
+		return Drop(err)
+	}
+	return h.Handle(WithEvent(c.Context(), event), event)
+}
+
+func (h *DeadLetterHandler) HandleGRPC(ctx context.Context, in *pb.TopicEventRequest) (*pb.TopicEventResponse, error) {
+	event := TopicEventCloudEvent(ctx, in)
+	if err := h.Handle(WithEvent(ctx, event), event); err != nil {
+		return nil, err
+	}
+	return StatusSuccess.TopicEventResponse(), nil
+}
+
+func (h *DeadLetterHandler) SubscriptionSDK() *common.Subscription {
+	return &common.Subscription{
+		PubsubName: h.PubsubName,
+		Topic:      h.Topic,
+		Route:      h.Route,
+	}
+}
+
+func (h *DeadLetterHandler) HandleSDK(ctx context.Context, e *common.TopicEvent) (retry bool, err error) {
+	event := cloudEventSDK(e)
+	err = h.Handle(WithEvent(ctx, event), event)
+	return StatusOf(err) == StatusRetry, err
+}
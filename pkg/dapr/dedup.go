@@ -0,0 +1,158 @@
+package dapr
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	pb "github.com/dapr/dapr/pkg/proto/runtime/v1"
+	"github.com/dapr/go-sdk/service/common"
+	"github.com/go-logr/logr"
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/pkedy/golang-dapr/pkg/components/state"
+	"github.com/pkedy/golang-dapr/pkg/errorz"
+)
+
+type (
+	// Dedup skips events that were already processed. Dapr delivers events
+	// at least once, so handlers with side effects can see the same event
+	// more than once. Events are identified by their source and ID, and
+	// recorded in a state store for `ttl` after they are handled successfully.
+	//
+	// An event that is redelivered while it is still being handled is not
+	// detected, so handlers should still tolerate the occasional duplicate.
+	Dedup struct {
+		log         logr.Logger
+		stateClient state.Store
+		store       string
+		ttl         time.Duration
+	}
+
+	// dedupHandler wraps an `EventHandler` with a `Dedup`.
+	dedupHandler struct {
+		EventHandler
+		dedup *Dedup
+	}
+
+	processedEvent struct {
+		ProcessedAt time.Time `json:"processedAt"`
+	}
+)
+
+// errNotProcessed is returned for handlers that respond with RETRY or DROP.
+var errNotProcessed = errors.New("event was not processed")
+
+func NewDedup(log logr.Logger, stateClient state.Store, store string, ttl time.Duration) *Dedup {
+	return &Dedup{
+		log:         log,
+		stateClient: stateClient,
+		store:       store,
+		ttl:         ttl,
+	}
+}
+
+// EventHandler wraps `handler` on all transports.
+func (d *Dedup) EventHandler(handler EventHandler) EventHandler {
+	return &dedupHandler{
+		EventHandler: handler,
+		dedup:        d,
+	}
+}
+
+// HTTP wraps a fiber topic event handler. Wrap the handler
+// before `HandleEvent` so that failed events are not recorded.
+func (d *Dedup) HTTP(handler fiber.Handler) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		event, err := ParseCloudEvent(c)
+		if err != nil {
+			// Let the handler decide what to do with the invalid event.
+			return handler(c)
+		}
+		return d.once(c.Context(), c.Path(), event.Source, event.ID, func() error {
+			return handler(c)
+		})
+	}
+}
+
+// GRPC wraps a `Server` topic event handler.
+func (d *Dedup) GRPC(handler TopicEventHandler) TopicEventHandler {
+	return func(ctx context.Context, in *pb.TopicEventRequest) (resp *pb.TopicEventResponse, err error) {
+		err = d.once(ctx, in.Path, in.Source, in.Id, func() error {
+			resp, err = handler(ctx, in)
+			if err == nil && resp != nil && resp.Status != pb.TopicEventResponse_SUCCESS {
+				return errNotProcessed
+			}
+			return err
+		})
+		if err == errNotProcessed {
+			return resp, nil
+		}
+		return resp, err
+	}
+}
+
+// SDK wraps a Go SDK topic event handler.
+func (d *Dedup) SDK(handler common.TopicEventHandler) common.TopicEventHandler {
+	return func(ctx context.Context, e *common.TopicEvent) (retry bool, err error) {
+		handled := false
+		err = d.once(ctx, e.PubsubName+"/"+e.Topic, e.Source, e.ID, func() error {
+			handled = true
+			retry, err = handler(ctx, e)
+			return err
+		})
+		if err != nil && !handled {
+			// Failures before the handler runs use the same statuses as handler errors.
+			return StatusOf(err) == StatusRetry, err
+		}
+		return retry, err
+	}
+}
+
+// once calls `handle` unless the event was already processed by the handler for `route`.
+func (d *Dedup) once(ctx context.Context, route, source, id string, handle func() error) error {
+	if id == "" {
+		return handle()
+	}
+	key := "processed:" + route + ":" + source + ":" + id
+
+	var processed processedEvent
+	err := d.stateClient.GetState(ctx, d.store, key, &processed)
+	if err == nil {
+		d.log.Info("Skipping duplicate event", "id", id, "source", source, "route", route,
+			"processedAt", processed.ProcessedAt)
+		return nil
+	}
+	if err := errorz.From(err); err.Code != 404 {
+		// Retry later rather than risk processing the event twice.
+		return Retry(err.WithMessage("could not check if event %q was processed", id))
+	}
+
+	if err := handle(); err != nil {
+		return err
+	}
+
+	// The event was handled, so failing to record it is only logged.
+	// Retrying would run the handler again.
+	if err := d.stateClient.SetState(ctx, d.store, []state.Item{{
+		Key: key,
+		Value: processedEvent{
+			ProcessedAt: time.Now().UTC(),
+		},
+	}}, state.WithTTL(d.ttl)); err != nil {
+		d.log.Error(err, "could not record processed event", "id", id, "source", source, "route", route)
+	}
+	return nil
+}
+
+func (h *dedupHandler) HandleHTTP(c *fiber.Ctx) error {
+	return h.dedup.HTTP(h.EventHandler.HandleHTTP)(c)
+}
+
+func (h *dedupHandler) HandleGRPC(ctx context.Context, in *pb.TopicEventRequest) (*pb.TopicEventResponse, error) {
+	return h.dedup.GRPC(h.EventHandler.HandleGRPC)(ctx, in)
+}
+
+func (h *dedupHandler) HandleSDK(ctx context.Context, e *common.TopicEvent) (bool, error) {
+	return h.dedup.SDK(h.EventHandler.HandleSDK)(ctx, e)
+}
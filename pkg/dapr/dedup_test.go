@@ -0,0 +1,171 @@
+package dapr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/dapr/dapr/pkg/proto/runtime/v1"
+	"github.com/dapr/go-sdk/service/common"
+	"github.com/go-logr/logr"
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/pkedy/golang-dapr/pkg/components/state"
+	"github.com/pkedy/golang-dapr/pkg/errorz"
+)
+
+// dedupStore keeps processed events in memory. Calls that are not
+// implemented panic through the nil embedded `state.Store`.
+type dedupStore struct {
+	state.Store
+	keys    map[string]bool
+	getErr  error
+	setOpts state.Options
+}
+
+func (s *dedupStore) GetState(ctx context.Context, store string, key string, target interface{}, opts ...state.Option) error {
+	if s.getErr != nil {
+		return s.getErr
+	}
+	if !s.keys[key] {
+		return errorz.NotFound("key %q not found", key)
+	}
+	return json.Unmarshal([]byte(`{"processedAt":"2022-07-01T12:00:00Z"}`), target)
+}
+
+func (s *dedupStore) SetState(ctx context.Context, store string, items []state.Item, opts ...state.Option) error {
+	s.setOpts = state.NewOptions(opts...)
+	for _, item := range items {
+		s.keys[item.Key] = true
+	}
+	return nil
+}
+
+// countingHandler counts the events it handles and fails with `err`.
+type countingHandler struct {
+	calls int
+	err   error
+}
+
+func (c *countingHandler) eventHandler(dedup *Dedup) EventHandler {
+	h := Handle("pubsub", "inventory", Rule{
+		Match: `event.type == "widget"`,
+		Path:  "/widgets",
+	}, func(ctx context.Context, _ *struct{}) error {
+		c.calls++
+		return c.err
+	})
+	return NewEventHandlers(logr.Discard(), h).WithDedup(dedup).handlers[0]
+}
+
+// deliverers deliver an event with `id` to a handler on each transport.
+var deliverers = map[string]func(t *testing.T, h EventHandler, id string) error{
+	"http": func(t *testing.T, h EventHandler, id string) error {
+		var err error
+		app := fiber.New()
+		app.Post("/widgets", func(c *fiber.Ctx) error {
+			err = h.HandleHTTP(c)
+			return nil
+		})
+		req := httptest.NewRequest(http.MethodPost, "/widgets", strings.NewReader(
+			`{"specversion":"1.0","id":"`+id+`","source":"inventory","type":"widget","data":{}}`))
+		req.Header.Set("Content-Type", "application/cloudevents+json")
+		if _, testErr := app.Test(req); testErr != nil {
+			t.Fatal(testErr)
+		}
+		return err
+	},
+	"grpc": func(t *testing.T, h EventHandler, id string) error {
+		_, err := h.HandleGRPC(context.Background(), &pb.TopicEventRequest{
+			Id:              id,
+			Source:          "inventory",
+			Type:            "widget",
+			SpecVersion:     "1.0",
+			DataContentType: "application/json",
+			Data:            []byte(`{}`),
+			Topic:           "inventory",
+			PubsubName:      "pubsub",
+			Path:            "/widgets",
+		})
+		return err
+	},
+	"sdk": func(t *testing.T, h EventHandler, id string) error {
+		retry, err := h.HandleSDK(context.Background(), &common.TopicEvent{
+			ID:              id,
+			Source:          "inventory",
+			Type:            "widget",
+			SpecVersion:     "1.0",
+			DataContentType: "application/json",
+			RawData:         []byte(`{}`),
+			Topic:           "inventory",
+			PubsubName:      "pubsub",
+		})
+		if err != nil && !retry {
+			t.Errorf("expected %v to be retried", err)
+		}
+		return err
+	},
+}
+
+func TestDedupSkipsDuplicates(t *testing.T) {
+	for name, deliver := range deliverers {
+		t.Run(name, func(t *testing.T) {
+			store := &dedupStore{keys: make(map[string]bool)}
+			var handler countingHandler
+			h := handler.eventHandler(NewDedup(logr.Discard(), store, "statestore", time.Hour))
+			for _, id := range []string{"1", "1", "2"} {
+				if err := deliver(t, h, id); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if handler.calls != 2 {
+				t.Errorf("expected 2 events to be handled, got %d", handler.calls)
+			}
+			if ttl := store.setOpts.Metadata[state.MetadataTTL]; ttl != "3600" {
+				t.Errorf("expected the processed event to expire after 3600 seconds, got %q", ttl)
+			}
+		})
+	}
+}
+
+func TestDedupRetriesFailures(t *testing.T) {
+	for name, deliver := range deliverers {
+		t.Run(name, func(t *testing.T) {
+			store := &dedupStore{keys: make(map[string]bool)}
+			handler := countingHandler{err: Retry(errors.New("database is down"))}
+			h := handler.eventHandler(NewDedup(logr.Discard(), store, "statestore", time.Hour))
+			if err := deliver(t, h, "1"); err == nil {
+				t.Fatal("expected the handler error")
+			}
+			handler.err = nil
+			if err := deliver(t, h, "1"); err != nil {
+				t.Fatal(err)
+			}
+			if handler.calls != 2 {
+				t.Errorf("expected the failed event to be handled again, got %d calls", handler.calls)
+			}
+		})
+	}
+}
+
+func TestDedupCheckFails(t *testing.T) {
+	for name, deliver := range deliverers {
+		t.Run(name, func(t *testing.T) {
+			store := &dedupStore{getErr: errorz.Unavailable("state store is down")}
+			var handler countingHandler
+			h := handler.eventHandler(NewDedup(logr.Discard(), store, "statestore", time.Hour))
+			err := deliver(t, h, "1")
+			if StatusOf(err) != StatusRetry {
+				t.Errorf("expected the event to be retried, got %v", err)
+			}
+			if handler.calls != 0 {
+				t.Errorf("expected the event not to be handled, got %d calls", handler.calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package dapr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc/status"
+
+	"github.com/pkedy/golang-dapr/pkg/errorz"
+)
+
+// grpcError converts an error returned by the Dapr sidecar over gRPC
+// using its status code. Dapr responds with `Aborted` when an ETag
+// does not match the stored value.
+func grpcError(err error, format string, args ...interface{}) *errorz.Error {
+	st, ok := grpcStatus(err)
+	if !ok {
+		if ctxErr := contextError(err, format, args...); ctxErr != nil {
+			return ctxErr
+		}
+		return errorz.Internal(err, format, args...)
+	}
+	if e := errorz.FromCode(st.Code(), format, args...); e.Type != errorz.TypeInternal {
+		return e.WithError(err)
+	}
+	return errorz.Internal(err, format, args...)
+}
+
+// contextError converts context cancellation and deadline errors.
+// It returns nil for any other error.
+func contextError(err error, format string, args ...interface{}) *errorz.Error {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return errorz.DeadlineExceeded(format, args...).WithError(err)
+	case errors.Is(err, context.Canceled):
+		return errorz.Canceled(format, args...).WithError(err)
+	}
+	return nil
+}
+
+// statusError converts an unexpected HTTP status code from the Dapr sidecar.
+func statusError(code int, body []byte, format string, args ...interface{}) *errorz.Error {
+	err := fmt.Errorf("received %d status: %s", code, body)
+	if e := errorz.FromHTTP(code, format, args...); e.Type != errorz.TypeInternal {
+		return e.WithError(err)
+	}
+	return errorz.Internal(err, format, args...)
+}
+
+// grpcStatus finds the gRPC status in `err`, which may be wrapped (i.e. by the SDK).
+func grpcStatus(err error) (*status.Status, bool) {
+	var se interface {
+		GRPCStatus() *status.Status
+	}
+	if errors.As(err, &se) {
+		return se.GRPCStatus(), true
+	}
+	return nil, false
+}
@@ -0,0 +1,220 @@
+package dapr
+
+import (
+	"context"
+
+	pb "github.com/dapr/dapr/pkg/proto/runtime/v1"
+	"github.com/dapr/go-sdk/service/common"
+	"github.com/go-logr/logr"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/multierr"
+)
+
+type (
+	// EventHandler is a topic event handler that can be served
+	// by every transport: fiber, `Server` and the Go SDK.
+	EventHandler interface {
+		Subscription() Subscription
+		Path() string
+		HandleHTTP(c *fiber.Ctx) error
+		HandleGRPC(ctx context.Context, in *pb.TopicEventRequest) (*pb.TopicEventResponse, error)
+		SubscriptionSDK() *common.Subscription
+		HandleSDK(ctx context.Context, e *common.TopicEvent) (retry bool, err error)
+	}
+
+	// Validator is implemented by event data that can check itself.
+	Validator interface {
+		Validate() error
+	}
+
+	// Handler handles events on a topic whose data decodes to `T`.
+	// If `*T` is a `Validator`, invalid data is dropped.
+	// An empty `Rule.Match` makes `Rule.Path` the default route.
+	Handler[T any] struct {
+		PubsubName      string
+		Topic           string
+		Rule            Rule
+		Metadata        map[string]string
+		DeadLetterTopic string
+		Decode          func(contentType string, data []byte, target *T) error
+		Handle          func(ctx context.Context, data *T) error
+	}
+
+	// EventHandlers serves a feature's event handlers on all transports.
+	// Embed it in a service to implement `Subscriber`, `Events` and `HandlerRegistrar`.
+	EventHandlers struct {
+		log      logr.Logger
+		handlers []EventHandler
+	}
+)
+
+// Handle creates a handler for events on `topic` that are routed by `rule`.
+func Handle[T any](pubsubName, topic string, rule Rule, handle func(ctx context.Context, data *T) error) *Handler[T] {
+	return &Handler[T]{
+		PubsubName: pubsubName,
+		Topic:      topic,
+		Rule:       rule,
+		Handle:     handle,
+	}
+}
+
+// WithPriority sets the priority of the routing rule.
+func (h *Handler[T]) WithPriority(priority int) *Handler[T] {
+	h.Rule.Priority = priority
+	return h
+}
+
+func (h *Handler[T]) WithMetadata(metadata map[string]string) *Handler[T] {
+	h.Metadata = metadata
+	return h
+}
+
+// WithDeadLetterTopic sets the topic that receives events that could not be delivered.
+// It applies to the whole topic subscription, so every handler of the topic
+// should use the same dead-letter topic.
+func (h *Handler[T]) WithDeadLetterTopic(topic string) *Handler[T] {
+	h.DeadLetterTopic = topic
+	return h
+}
+
+// WithDecoder replaces `DecodeData` for decoding event data.
+func (h *Handler[T]) WithDecoder(decode func(contentType string, data []byte, target *T) error) *Handler[T] {
+	h.Decode = decode
+	return h
+}
+
+func (h *Handler[T]) Subscription() Subscription {
+	sub := Subscription{
+		PubsubName:      h.PubsubName,
+		Topic:           h.Topic,
+		Metadata:        h.Metadata,
+		DeadLetterTopic: h.DeadLetterTopic,
+	}
+	if h.Rule.Match == "" {
+		sub.Routes.Default = h.Rule.Path
+	} else {
+		sub.Routes.Rules = []Rule{h.Rule}
+	}
+	return sub
+}
+
+func (h *Handler[T]) Path() string {
+	return h.Rule.Path
+}
+
+func (h *Handler[T]) HandleHTTP(c *fiber.Ctx) error {
+	event, err := ParseCloudEvent(c)
+	if err != nil {
+		return Drop(err)
+	}
+	return h.handle(c.Context(), event)
+}
+
+func (h *Handler[T]) HandleGRPC(ctx context.Context, in *pb.TopicEventRequest) (*pb.TopicEventResponse, error) {
+	if err := h.handle(ctx, TopicEventCloudEvent(ctx, in)); err != nil {
+		return nil, err
+	}
+	return StatusSuccess.TopicEventResponse(), nil
+}
+
+// SubscriptionSDK converts the subscription for the Go SDK, which does
+// not support dead-letter topics.
+func (h *Handler[T]) SubscriptionSDK() *common.Subscription {
+	return &common.Subscription{
+		PubsubName: h.PubsubName,
+		Topic:      h.Topic,
+		Metadata:   h.Metadata,
+		Route:      h.Rule.Path,
+		Match:      h.Rule.Match,
+		Priority:   h.Rule.Priority,
+	}
+}
+
+func (h *Handler[T]) HandleSDK(ctx context.Context, e *common.TopicEvent) (retry bool, err error) {
+	err = h.handle(ctx, cloudEventSDK(e))
+	return StatusOf(err) == StatusRetry, err
+}
+
+// handle decodes the data and calls the handler with the event in `ctx`.
+// Data that cannot be decoded or is invalid is dropped, since
+// redelivering the same event would fail the same way.
+func (h *Handler[T]) handle(ctx context.Context, event *CloudEvent) error {
+	var target T
+	decode := h.Decode
+	if decode == nil {
+		decode = func(contentType string, data []byte, target *T) error {
+			return DecodeData(contentType, data, target)
+		}
+	}
+	if err := decode(event.DataContentType, event.Bytes(), &target); err != nil {
+		return Drop(err)
+	}
+	if v, ok := any(&target).(Validator); ok {
+		if err := v.Validate(); err != nil {
+			return Drop(err)
+		}
+	}
+	return h.Handle(WithEvent(ctx, event), &target)
+}
+
+// cloudEventSDK converts an SDK topic event to a CloudEvent.
+func cloudEventSDK(e *common.TopicEvent) *CloudEvent {
+	event := CloudEvent{
+		ID:              e.ID,
+		Source:          e.Source,
+		SpecVersion:     e.SpecVersion,
+		Type:            e.Type,
+		DataContentType: e.DataContentType,
+		Subject:         e.Subject,
+	}
+	event.setData(e.RawData)
+	event.SetExtension(ExtensionTopic, e.Topic)
+	event.SetExtension(ExtensionPubsubName, e.PubsubName)
+	return &event
+}
+
+func NewEventHandlers(log logr.Logger, handlers ...EventHandler) *EventHandlers {
+	return &EventHandlers{
+		log:      log,
+		handlers: handlers,
+	}
+}
+
+// WithDedup wraps every handler with `dedup` so that events that were
+// already processed are skipped. A nil `dedup` leaves the handlers as is.
+func (e *EventHandlers) WithDedup(dedup *Dedup) *EventHandlers {
+	if dedup != nil {
+		for i, h := range e.handlers {
+			e.handlers[i] = dedup.EventHandler(h)
+		}
+	}
+	return e
+}
+
+func (e *EventHandlers) Subscriptions() []Subscription {
+	subs := make([]Subscription, len(e.handlers))
+	for i, h := range e.handlers {
+		subs[i] = h.Subscription()
+	}
+	return subs
+}
+
+func (e *EventHandlers) RegisterEventHandlers(app *fiber.App) {
+	for _, h := range e.handlers {
+		app.Post(h.Path(), HandleEvent(e.log, h.HandleHTTP))
+	}
+}
+
+func (e *EventHandlers) RegisterTopicEventHandlers(register RegisterEventHandler) {
+	for _, h := range e.handlers {
+		register(h.Path(), h.HandleGRPC)
+	}
+}
+
+func (e *EventHandlers) RegisterTopicEventHandlersSDK(service common.Service) error {
+	var err error
+	for _, h := range e.handlers {
+		err = multierr.Append(err, service.AddTopicEventHandler(h.SubscriptionSDK(), h.HandleSDK))
+	}
+	return err
+}
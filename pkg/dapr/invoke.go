@@ -0,0 +1,100 @@
+package dapr
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type (
+	// Invocation is a service invocation received from Dapr
+	// over either HTTP or gRPC.
+	Invocation struct {
+		Method      string
+		Verb        string
+		Query       url.Values
+		ContentType string
+		Data        []byte
+	}
+
+	InvocationResult struct {
+		ContentType string
+		Data        []byte
+	}
+
+	InvokeHandler func(ctx context.Context, in *Invocation) (*InvocationResult, error)
+
+	// RegisterInvokeHandler registers `handler` for `method`.
+	// An empty `verb` handles every HTTP verb.
+	RegisterInvokeHandler func(verb, method string, handler InvokeHandler)
+
+	InvokeRegistrar interface {
+		RegisterInvokeHandlers(register RegisterInvokeHandler)
+	}
+)
+
+// Decode unmarshals the invocation data into `target`.
+func (in *Invocation) Decode(target interface{}) error {
+	return json.Unmarshal(in.Data, target)
+}
+
+// JSONResult serializes `val` as the invocation result.
+func JSONResult(val interface{}) (*InvocationResult, error) {
+	data, err := json.Marshal(val)
+	if err != nil {
+		return nil, err
+	}
+	return &InvocationResult{
+		ContentType: fiber.MIMEApplicationJSON,
+		Data:        data,
+	}, nil
+}
+
+// RegisterInvokeHandlers mounts the invocable methods of `registrars`
+// on `app` at the paths Dapr uses to call the app over HTTP.
+func RegisterInvokeHandlers(app *fiber.App, registrars ...InvokeRegistrar) {
+	for _, r := range registrars {
+		r.RegisterInvokeHandlers(func(verb, method string, handler InvokeHandler) {
+			route := "/" + strings.TrimPrefix(method, "/")
+			h := invokeHandlerHTTP(method, handler)
+			if verb == "" {
+				app.All(route, h)
+			} else {
+				app.Add(strings.ToUpper(verb), route, h)
+			}
+		})
+	}
+}
+
+func invokeHandlerHTTP(method string, handler InvokeHandler) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		query, err := url.ParseQuery(string(c.Request().URI().QueryString()))
+		if err != nil {
+			return err
+		}
+		result, err := handler(c.Context(), &Invocation{
+			Method:      method,
+			Verb:        c.Method(),
+			Query:       query,
+			ContentType: c.Get(fiber.HeaderContentType),
+			Data:        append([]byte(nil), c.Body()...),
+		})
+		if err != nil {
+			return err
+		}
+		if result == nil {
+			return c.SendStatus(fiber.StatusNoContent)
+		}
+		if result.ContentType != "" {
+			c.Set(fiber.HeaderContentType, result.ContentType)
+		}
+		return c.Send(result.Data)
+	}
+}
+
+func invokeKey(verb, method string) string {
+	return strings.ToUpper(verb) + " " + strings.TrimPrefix(method, "/")
+}
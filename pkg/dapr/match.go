@@ -0,0 +1,90 @@
+package dapr
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/checker/decls"
+	exprpb "google.golang.org/genproto/googleapis/api/expr/v1alpha1"
+	"google.golang.org/protobuf/proto"
+)
+
+// matchEnv is the same environment that Dapr uses to evaluate routing rules.
+var matchEnv *cel.Env
+
+func init() {
+	env, err := cel.NewEnv(cel.Declarations(
+		decls.NewVar("event", decls.NewMapType(decls.String, decls.Dyn)),
+	))
+	if err != nil {
+		panic(fmt.Errorf("could not create CEL environment: %w", err))
+	}
+	matchEnv = env
+}
+
+// ValidateMatch checks that `match` is a boolean CEL expression. Event
+// attributes must be context attributes or valid extension names, which
+// includes the extensions Dapr adds (e.g. `topic`) and custom extensions.
+func ValidateMatch(match string) error {
+	if strings.TrimSpace(match) == "" {
+		return errors.New("match expression is empty")
+	}
+	ast, issues := matchEnv.Compile(match)
+	if issues != nil && issues.Err() != nil {
+		return issues.Err()
+	}
+	if t := ast.ResultType(); !proto.Equal(t, decls.Bool) && !proto.Equal(t, decls.Dyn) {
+		return fmt.Errorf("match expression %q does not return a boolean", match)
+	}
+	var invalid []string
+	walkEventAttributes(ast.Expr(), func(attr string) {
+		if _, ok := contextAttributes[attr]; !ok && !validExtensionName(attr) {
+			invalid = append(invalid, attr)
+		}
+	})
+	if len(invalid) > 0 {
+		sort.Strings(invalid)
+		return fmt.Errorf("invalid event attributes in %q: %s", match, strings.Join(invalid, ", "))
+	}
+	return nil
+}
+
+// walkEventAttributes calls `fn` for every `event.<attribute>` in `expr`.
+func walkEventAttributes(expr *exprpb.Expr, fn func(attr string)) {
+	if expr == nil {
+		return
+	}
+	switch e := expr.ExprKind.(type) {
+	case *exprpb.Expr_SelectExpr:
+		sel := e.SelectExpr
+		if ident := sel.Operand.GetIdentExpr(); ident != nil && ident.Name == "event" {
+			fn(sel.Field)
+			return
+		}
+		walkEventAttributes(sel.Operand, fn)
+	case *exprpb.Expr_CallExpr:
+		walkEventAttributes(e.CallExpr.Target, fn)
+		for _, arg := range e.CallExpr.Args {
+			walkEventAttributes(arg, fn)
+		}
+	case *exprpb.Expr_ListExpr:
+		for _, elem := range e.ListExpr.Elements {
+			walkEventAttributes(elem, fn)
+		}
+	case *exprpb.Expr_StructExpr:
+		for _, entry := range e.StructExpr.Entries {
+			walkEventAttributes(entry.GetMapKey(), fn)
+			walkEventAttributes(entry.Value, fn)
+		}
+	case *exprpb.Expr_ComprehensionExpr:
+		c := e.ComprehensionExpr
+		walkEventAttributes(c.IterRange, fn)
+		walkEventAttributes(c.AccuInit, fn)
+		walkEventAttributes(c.LoopCondition, fn)
+		walkEventAttributes(c.LoopStep, fn)
+		walkEventAttributes(c.Result, fn)
+	}
+}
@@ -0,0 +1,76 @@
+package dapr
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateMatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		match string
+		valid bool
+	}{
+		{"context attribute", `event.type == "widget"`, true},
+		{"dapr extension", `event.topic == "inventory"`, true},
+		{"custom extension", `event.tenant == "acme" && event.type == "widget"`, true},
+		{"data field", `event.data.price > 100`, true},
+		{"macro", `["widget", "gadget"].exists(t, event.type == t)`, true},
+		{"empty", "  ", false},
+		{"syntax error", `event.type ==`, false},
+		{"dynamic result", `event.type`, true},
+		{"string result", `"widget"`, false},
+		{"undeclared variable", `widget.type == "widget"`, false},
+		{"invalid extension", `event.Tenant == "acme"`, false},
+		{"invalid extension in call", `event.type.startsWith(event.tenant_id)`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateMatch(tt.match)
+			if valid := err == nil; valid != tt.valid {
+				t.Errorf("expected valid to be %t, got %v", tt.valid, err)
+			}
+		})
+	}
+}
+
+func TestValidateSubscriptions(t *testing.T) {
+	sub := func(rules ...Rule) []*Subscription {
+		return []*Subscription{{
+			PubsubName: "pubsub",
+			Topic:      "inventory",
+			Routes:     Routes{Rules: rules, Default: "/default"},
+		}}
+	}
+	handlers := map[string]bool{"/widgets": true, "/gadgets": true, "/default": true}
+	tests := []struct {
+		name string
+		subs []*Subscription
+		want error
+	}{
+		{"valid", sub(
+			Rule{Match: `event.type == "widget"`, Path: "/widgets"},
+			Rule{Match: `event.type == "gadget"`, Path: "/gadgets"}), nil},
+		{"duplicate path", sub(
+			Rule{Match: `event.type == "widget"`, Path: "/widgets"},
+			Rule{Match: `event.type == "gadget"`, Path: "/widgets"}), ErrDuplicatePath},
+		{"duplicate match", sub(
+			Rule{Match: `event.type == "widget"`, Path: "/widgets"},
+			Rule{Match: `event.type == "widget"`, Path: "/gadgets"}), ErrDuplicateMatch},
+		{"invalid match", sub(
+			Rule{Match: `event.type ==`, Path: "/widgets"}), ErrInvalidMatch},
+		{"missing handler", sub(
+			Rule{Match: `event.type == "thing"`, Path: "/things"}), ErrMissingHandler},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSubscriptions(tt.subs, func(path string) bool { return handlers[path] })
+			if tt.want == nil && err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -3,11 +3,21 @@ package dapr
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"sort"
+	"strings"
 
 	cpb "github.com/dapr/dapr/pkg/proto/common/v1"
 	pb "github.com/dapr/dapr/pkg/proto/runtime/v1"
 	"github.com/go-logr/logr"
 	"github.com/golang/protobuf/ptypes/empty"
+	"go.uber.org/multierr"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/pkedy/golang-dapr/pkg/errorz"
 )
 
 type (
@@ -15,9 +25,11 @@ type (
 	RegisterEventHandler func(path string, handler TopicEventHandler)
 
 	Server struct {
-		log           logr.Logger
-		handlers      map[string]TopicEventHandler
-		subscriptions []*Subscription
+		log             logr.Logger
+		handlers        map[string]TopicEventHandler
+		bindingHandlers map[string]BindingHandler
+		invokeHandlers  map[string]InvokeHandler
+		subscriptions   []*Subscription
 	}
 
 	HandlerRegistrar interface {
@@ -27,38 +39,155 @@ type (
 
 func NewServer(log logr.Logger) *Server {
 	return &Server{
-		log:           log,
-		handlers:      make(map[string]TopicEventHandler),
-		subscriptions: make([]*Subscription, 0, 10),
+		log:             log,
+		handlers:        make(map[string]TopicEventHandler),
+		bindingHandlers: make(map[string]BindingHandler),
+		invokeHandlers:  make(map[string]InvokeHandler),
+		subscriptions:   make([]*Subscription, 0, 10),
+	}
+}
+
+func (s *Server) RegisterInvokeHandler(verb, method string, handler InvokeHandler) {
+	s.invokeHandlers[invokeKey(verb, method)] = handler
+}
+
+func (s *Server) RegisterInvokeHandlers(registrars ...InvokeRegistrar) {
+	for _, r := range registrars {
+		r.RegisterInvokeHandlers(s.RegisterInvokeHandler)
 	}
 }
 
 func (s *Server) OnInvoke(ctx context.Context, in *cpb.InvokeRequest) (*cpb.InvokeResponse, error) {
-	return nil, nil
+	invocation := Invocation{
+		Method:      in.Method,
+		ContentType: in.ContentType,
+		Data:        in.Data.GetValue(),
+	}
+	if ext := in.HttpExtension; ext != nil {
+		if ext.Verb != cpb.HTTPExtension_NONE {
+			invocation.Verb = ext.Verb.String()
+		}
+		query, err := url.ParseQuery(ext.Querystring)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid querystring for method %q: %v", in.Method, err)
+		}
+		invocation.Query = query
+	}
+
+	handler, ok := s.invokeHandler(invocation.Verb, in.Method)
+	if !ok {
+		s.log.Error(nil, "invoke handler not found", "method", in.Method, "verb", invocation.Verb)
+		return nil, status.Errorf(codes.Unimplemented, "handler not found for method %q", in.Method)
+	}
+
+	result, err := handler(ctx, &invocation)
+	if err != nil {
+		return nil, errorz.ToStatus(err).Err()
+	}
+	if result == nil {
+		return &cpb.InvokeResponse{}, nil
+	}
+	return &cpb.InvokeResponse{
+		Data:        &anypb.Any{Value: result.Data},
+		ContentType: result.ContentType,
+	}, nil
+}
+
+// invokeHandler returns the handler for `verb` and `method`. gRPC
+// invocations without an HTTP extension have no verb, so they use
+// the handler for the method if only one verb is registered.
+func (s *Server) invokeHandler(verb, method string) (InvokeHandler, bool) {
+	if handler, ok := s.invokeHandlers[invokeKey(verb, method)]; ok {
+		return handler, true
+	}
+	if handler, ok := s.invokeHandlers[invokeKey("", method)]; ok {
+		return handler, true
+	}
+	if verb != "" {
+		return nil, false
+	}
+	var found InvokeHandler
+	for key, handler := range s.invokeHandlers {
+		if m := key[strings.Index(key, " ")+1:]; m != strings.TrimPrefix(method, "/") {
+			continue
+		}
+		if found != nil {
+			return nil, false
+		}
+		found = handler
+	}
+	return found, found != nil
 }
 
 func (s *Server) ListInputBindings(ctx context.Context, in *empty.Empty) (*pb.ListInputBindingsResponse, error) {
+	bindings := make([]string, 0, len(s.bindingHandlers))
+	for name := range s.bindingHandlers {
+		bindings = append(bindings, name)
+	}
+	sort.Strings(bindings)
+	s.log.Info("ListInputBindings called", "bindings", bindings)
+
 	return &pb.ListInputBindingsResponse{
-		Bindings: []string{},
+		Bindings: bindings,
 	}, nil
 }
 
+func (s *Server) RegisterBindingHandler(name string, handler BindingHandler) {
+	s.bindingHandlers[name] = handler
+}
+
+func (s *Server) RegisterBindingHandlers(registrars ...BindingRegistrar) {
+	for _, r := range registrars {
+		r.RegisterBindingHandlers(s.RegisterBindingHandler)
+	}
+}
+
 func (s *Server) OnBindingEvent(ctx context.Context, in *pb.BindingEventRequest) (*pb.BindingEventResponse, error) {
-	return &pb.BindingEventResponse{}, nil
+	handler, ok := s.bindingHandlers[in.Name]
+	if !ok {
+		s.log.Error(nil, "binding handler not found", "name", in.Name)
+		return nil, fmt.Errorf("handler not found for binding %q", in.Name)
+	}
+
+	out, err := handler(ctx, &BindingEvent{
+		Name:     in.Name,
+		Data:     in.Data,
+		Metadata: in.Metadata,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.BindingEventResponse{
+		Data: out,
+	}, nil
 }
 
-func (s *Server) Subscribe(subscriptions []*Subscription) {
+// Subscribe rejects subscriptions with bulk subscribe enabled
+// because Dapr's gRPC API cannot deliver batched messages.
+func (s *Server) Subscribe(subscriptions []*Subscription) error {
+	var errs error
+	for _, sub := range subscriptions {
+		if sub.BulkSubscribe != nil && sub.BulkSubscribe.Enabled {
+			errs = multierr.Append(errs, fmt.Errorf("%w over gRPC: pubsub %q topic %q",
+				ErrBulkUnsupported, sub.PubsubName, sub.Topic))
+		}
+	}
+	if errs != nil {
+		return errs
+	}
 	s.subscriptions = append(s.subscriptions, subscriptions...)
+	return nil
 }
 
 func (s *Server) ListTopicSubscriptions(ctx context.Context, in *empty.Empty) (*pb.ListTopicSubscriptionsResponse, error) {
 	subs := make([]*pb.TopicSubscription, len(s.subscriptions))
 	for i, s := range s.subscriptions {
 		subs[i] = &pb.TopicSubscription{
-			PubsubName: s.PubsubName,
-			Topic:      s.Topic,
-			Routes:     convertRoutes(s.Routes),
-			Metadata:   s.Metadata,
+			PubsubName:      s.PubsubName,
+			Topic:           s.Topic,
+			Routes:          convertRoutes(s.Routes),
+			Metadata:        s.Metadata,
+			DeadLetterTopic: s.DeadLetterTopic,
 		}
 	}
 	s.log.Info("ListTopicSubscriptions called", "subscriptions", subs)
@@ -82,6 +211,11 @@ func convertRoutes(routes Routes) *pb.TopicRoutes {
 	}
 }
 
+func (s *Server) HasTopicEventHandler(path string) bool {
+	_, ok := s.handlers[path]
+	return ok
+}
+
 func (s *Server) RegisterTopicEventHandler(path string, handler TopicEventHandler) {
 	s.handlers[path] = handler
 }
@@ -99,5 +233,52 @@ func (s *Server) OnTopicEvent(ctx context.Context, in *pb.TopicEventRequest) (*p
 		return nil, fmt.Errorf("handler not found for path %q", in.Path)
 	}
 
-	return handler(ctx, in)
+	// Errors are converted to a status so the handler can choose
+	// between retrying and dropping the message.
+	resp, err := handler(ctx, in)
+	if err != nil {
+		status := StatusOf(err)
+		s.log.Error(err, "error handling event", "path", in.Path, "id", in.Id, "status", status)
+		return status.TopicEventResponse(), nil
+	}
+	if resp == nil {
+		return StatusSuccess.TopicEventResponse(), nil
+	}
+	return resp, nil
+}
+
+// TopicEventCloudEvent converts a topic event request to the CloudEvent
+// that the HTTP handlers receive, so handlers can use the same attributes
+// on every transport. Data is kept in `Data` for JSON content types and
+// in `DataBase64` for anything else. The topic and pubsub name are set
+// as extensions, and Dapr sends the tracing attributes as gRPC metadata.
+func TopicEventCloudEvent(ctx context.Context, in *pb.TopicEventRequest) *CloudEvent {
+	event := CloudEvent{
+		ID:              in.Id,
+		Source:          in.Source,
+		SpecVersion:     in.SpecVersion,
+		Type:            in.Type,
+		DataContentType: in.DataContentType,
+	}
+	event.setData(in.Data)
+	event.SetExtension(ExtensionTopic, in.Topic)
+	event.SetExtension(ExtensionPubsubName, in.PubsubName)
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for _, name := range []string{ExtensionTraceParent, ExtensionTraceState} {
+			if values := md.Get(name); len(values) > 0 {
+				event.SetExtension(name, values[0])
+			}
+		}
+	}
+	return &event
+}
+
+// DecodeTopicEvent decodes the data of a topic event request into `target`
+// based on its content type. If `ce` is not nil, it is set to the event.
+func DecodeTopicEvent(ctx context.Context, in *pb.TopicEventRequest, ce *CloudEvent, target interface{}) error {
+	event := TopicEventCloudEvent(ctx, in)
+	if ce != nil {
+		*ce = *event
+	}
+	return event.DecodeData(target)
 }
@@ -0,0 +1,92 @@
+package dapr
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	cpb "github.com/dapr/dapr/pkg/proto/common/v1"
+	"github.com/go-logr/logr"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/pkedy/golang-dapr/pkg/errorz"
+)
+
+// invokeServer returns a server with a handler that responds with
+// its verb for each of `verbs` on the "widgets" method.
+func invokeServer(verbs ...string) *Server {
+	s := NewServer(logr.Discard())
+	for _, verb := range verbs {
+		verb := verb
+		s.RegisterInvokeHandler(verb, "widgets", func(ctx context.Context, in *Invocation) (*InvocationResult, error) {
+			return &InvocationResult{Data: []byte(verb + " " + in.Query.Get("id"))}, nil
+		})
+	}
+	return s
+}
+
+func invokeRequest(verb cpb.HTTPExtension_Verb, method, query string) *cpb.InvokeRequest {
+	return &cpb.InvokeRequest{
+		Method: method,
+		HttpExtension: &cpb.HTTPExtension{
+			Verb:        verb,
+			Querystring: query,
+		},
+	}
+}
+
+func TestOnInvokeRouting(t *testing.T) {
+	tests := []struct {
+		name  string
+		verbs []string
+		req   *cpb.InvokeRequest
+		want  string
+		code  codes.Code
+	}{
+		{"verb", []string{"GET", "POST"}, invokeRequest(cpb.HTTPExtension_POST, "widgets", "id=1"), "POST 1", codes.OK},
+		{"any verb", []string{""}, invokeRequest(cpb.HTTPExtension_DELETE, "/widgets", "id=1"), " 1", codes.OK},
+		{"verbless", []string{"GET"}, &cpb.InvokeRequest{Method: "widgets"}, "GET ", codes.OK},
+		{"verbless extension", []string{"GET"}, invokeRequest(cpb.HTTPExtension_NONE, "widgets", "id=1"), "GET 1", codes.OK},
+		{"verbless ambiguous", []string{"GET", "POST"}, &cpb.InvokeRequest{Method: "widgets"}, "", codes.Unimplemented},
+		{"unknown verb", []string{"GET"}, invokeRequest(cpb.HTTPExtension_PUT, "widgets", ""), "", codes.Unimplemented},
+		{"unknown method", []string{"GET"}, invokeRequest(cpb.HTTPExtension_GET, "gadgets", ""), "", codes.Unimplemented},
+		{"invalid querystring", []string{"GET"}, invokeRequest(cpb.HTTPExtension_GET, "widgets", "id=%zz"), "", codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := invokeServer(tt.verbs...).OnInvoke(context.Background(), tt.req)
+			if code := status.Code(err); code != tt.code {
+				t.Fatalf("expected %s, got %s (%v)", tt.code, code, err)
+			}
+			if err == nil && string(resp.Data.GetValue()) != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, resp.Data.GetValue())
+			}
+		})
+	}
+}
+
+func TestOnInvokeErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code codes.Code
+	}{
+		{"not found", errorz.NotFound("widget not found"), codes.NotFound},
+		{"conflict", errorz.Conflict("widget was modified"), codes.Aborted},
+		{"invalid argument", errorz.InvalidArgument("invalid widget"), codes.InvalidArgument},
+		{"other", errors.New("boom"), codes.Internal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewServer(logr.Discard())
+			s.RegisterInvokeHandler("", "widgets", func(ctx context.Context, in *Invocation) (*InvocationResult, error) {
+				return nil, tt.err
+			})
+			_, err := s.OnInvoke(context.Background(), &cpb.InvokeRequest{Method: "widgets"})
+			if code := status.Code(err); code != tt.code {
+				t.Errorf("expected %s, got %s (%v)", tt.code, code, err)
+			}
+		})
+	}
+}
@@ -2,9 +2,11 @@ package dapr
 
 import (
 	"encoding/json"
+	"fmt"
 
 	"github.com/go-logr/logr"
 	"github.com/gofiber/fiber/v2"
+	"go.uber.org/multierr"
 )
 
 type (
@@ -15,6 +17,12 @@ type (
 	Events interface {
 		RegisterEventHandlers(app *fiber.App)
 	}
+
+	// SubscribeHTTP serves subscriptions to Dapr from a fiber app.
+	SubscribeHTTP struct {
+		log logr.Logger
+		app *fiber.App
+	}
 )
 
 func RegisterServices(app *fiber.App, services ...Service) {
@@ -29,22 +37,77 @@ func RegisterEventHandlers(app *fiber.App, events ...Events) {
 	}
 }
 
-func SubscribeHTTPHandler(log logr.Logger, app *fiber.App) func(subscriptions []*Subscription) {
-	return func(subscriptions []*Subscription) {
-		app.Get("/dapr/subscribe", func(c *fiber.Ctx) error {
-			log.Info("subscribe called", "subscriptions", subscriptions)
-			return c.JSON(subscriptions)
-		})
+// HandleBinding mounts `handler` for the input binding `name`.
+// Dapr sends an OPTIONS request on startup to discover which
+// bindings the app handles, then POSTs each event to the same path.
+func HandleBinding(app *fiber.App, name string, handler fiber.Handler) {
+	route := "/" + name
+	app.Options(route, func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	app.Post(route, handler)
+}
+
+func SubscribeHTTPHandler(log logr.Logger, app *fiber.App) *SubscribeHTTP {
+	return &SubscribeHTTP{
+		log: log,
+		app: app,
 	}
 }
 
+func (s *SubscribeHTTP) Subscribe(subscriptions []*Subscription) error {
+	s.app.Get("/dapr/subscribe", func(c *fiber.Ctx) error {
+		s.log.Info("subscribe called", "subscriptions", subscriptions)
+		return c.JSON(subscriptions)
+	})
+	return nil
+}
+
+// HasTopicEventHandler returns true if a POST route is registered for `path`.
+func (s *SubscribeHTTP) HasTopicEventHandler(path string) bool {
+	for _, routes := range s.app.Stack() {
+		for _, route := range routes {
+			if route.Method == fiber.MethodPost && route.Path == path {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func DecodeCloudEvent(c *fiber.Ctx, ce *CloudEvent, target interface{}) error {
-	var event CloudEvent
-	if err := c.BodyParser(&event); err != nil {
+	event, err := ParseCloudEvent(c)
+	if err != nil {
 		return err
 	}
 	if ce != nil {
-		*ce = event
+		*ce = *event
+	}
+	return event.DecodeData(target)
+}
+
+// ParseCloudEvent reads and validates the event in the request.
+// Events are in structured mode (the body is the JSON event) unless
+// the request has a `ce-specversion` header, in which case the event is
+// in binary mode (attributes are `ce-*` headers and the body is the data).
+func ParseCloudEvent(c *fiber.Ctx) (*CloudEvent, error) {
+	var event CloudEvent
+	if c.Get(headerPrefix+"specversion") != "" {
+		var errs error
+		c.Request().Header.VisitAll(func(key, value []byte) {
+			errs = multierr.Append(errs, event.setBinaryHeader(string(key), string(value)))
+		})
+		if errs != nil {
+			return nil, errs
+		}
+		event.DataContentType = c.Get(fiber.HeaderContentType)
+		// The body is only valid during the request.
+		event.setData(append([]byte(nil), c.Body()...))
+	} else if err := json.Unmarshal(c.Body(), &event); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCloudEvent, err)
+	}
+	if err := event.Validate(); err != nil {
+		return nil, err
 	}
-	return json.Unmarshal(event.Data, target)
+	return &event, nil
 }
@@ -0,0 +1,95 @@
+package dapr
+
+import (
+	"errors"
+
+	pb "github.com/dapr/dapr/pkg/proto/runtime/v1"
+	"github.com/go-logr/logr"
+	"github.com/gofiber/fiber/v2"
+)
+
+// EventStatus tells Dapr what to do with a delivered message.
+type EventStatus string
+
+const (
+	StatusSuccess EventStatus = "SUCCESS"
+	StatusRetry   EventStatus = "RETRY"
+	StatusDrop    EventStatus = "DROP"
+)
+
+// StatusError is an event handler error with an explicit status.
+// Handler errors without a status are retried.
+type StatusError struct {
+	Status EventStatus
+	Err    error
+}
+
+type topicEventResponse struct {
+	Status EventStatus `json:"status"`
+}
+
+// Drop returns `err` with instructions for Dapr to drop the message,
+// i.e. because it can never be processed successfully.
+func Drop(err error) error {
+	return &StatusError{
+		Status: StatusDrop,
+		Err:    err,
+	}
+}
+
+// Retry returns `err` with instructions for Dapr to redeliver the message.
+func Retry(err error) error {
+	return &StatusError{
+		Status: StatusRetry,
+		Err:    err,
+	}
+}
+
+// StatusOf returns the status for an event handler's error.
+func StatusOf(err error) EventStatus {
+	if err == nil {
+		return StatusSuccess
+	}
+	var se *StatusError
+	if errors.As(err, &se) {
+		return se.Status
+	}
+	return StatusRetry
+}
+
+func (e *StatusError) Error() string {
+	return string(e.Status) + ": " + e.Err.Error()
+}
+
+func (e *StatusError) Unwrap() error {
+	return e.Err
+}
+
+// TopicEventResponse converts the status to the gRPC response.
+func (s EventStatus) TopicEventResponse() *pb.TopicEventResponse {
+	status := pb.TopicEventResponse_RETRY
+	switch s {
+	case StatusSuccess:
+		status = pb.TopicEventResponse_SUCCESS
+	case StatusDrop:
+		status = pb.TopicEventResponse_DROP
+	}
+	return &pb.TopicEventResponse{
+		Status: status,
+	}
+}
+
+// HandleEvent wraps a fiber topic event handler and responds
+// with the status for its error, i.e. `{"status":"DROP"}`.
+func HandleEvent(log logr.Logger, handler fiber.Handler) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		err := handler(c)
+		status := StatusOf(err)
+		if err != nil {
+			log.Error(err, "error handling event", "path", c.Path(), "status", status)
+		}
+		return c.JSON(topicEventResponse{
+			Status: status,
+		})
+	}
+}
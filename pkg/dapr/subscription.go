@@ -2,17 +2,33 @@ package dapr
 
 import (
 	"errors"
+	"fmt"
+	"sort"
 
 	"github.com/go-logr/logr"
+	"go.uber.org/multierr"
+
+	"github.com/pkedy/golang-dapr/pkg/components/pubsub"
 )
 
 // Dapr subscription response
 type (
 	Subscription struct {
-		PubsubName string            `json:"pubsubname"`
-		Topic      string            `json:"topic"`
-		Metadata   map[string]string `json:"metadata,omitempty"`
-		Routes     Routes            `json:"routes"`
+		PubsubName      string            `json:"pubsubname"`
+		Topic           string            `json:"topic"`
+		Metadata        map[string]string `json:"metadata,omitempty"`
+		Routes          Routes            `json:"routes"`
+		DeadLetterTopic string            `json:"deadLetterTopic,omitempty"`
+		BulkSubscribe   *BulkSubscribe    `json:"bulkSubscribe,omitempty"`
+	}
+
+	// BulkSubscribe enables delivery of batched messages to the HTTP
+	// handlers of a subscription. See `HandleBulk`. The gRPC server
+	// rejects it because Dapr's gRPC API has no bulk delivery.
+	BulkSubscribe struct {
+		Enabled            bool  `json:"enabled"`
+		MaxMessagesCount   int32 `json:"maxMessagesCount,omitempty"`
+		MaxAwaitDurationMs int32 `json:"maxAwaitDurationMs,omitempty"`
 	}
 
 	Routes struct {
@@ -23,18 +39,62 @@ type (
 	Rule struct {
 		Match string `json:"match"`
 		Path  string `json:"path"`
+		// Priority orders the rules of a topic, lowest first.
+		// Dapr evaluates rules in order and uses the first match.
+		Priority int `json:"-"`
 	}
 
 	Subscriber interface {
 		Subscriptions() []Subscription
 	}
+
+	// SubscriptionTarget is a server that receives the merged subscriptions.
+	// It returns an error for subscriptions it cannot serve.
+	SubscriptionTarget interface {
+		Subscribe(subscriptions []*Subscription) error
+		HasTopicEventHandler(path string) bool
+	}
+)
+
+var (
+	ErrDuplicateDefaultRoute = errors.New("duplicate default route")
+	ErrDuplicateDeadLetter   = errors.New("duplicate dead-letter topic")
+	ErrDuplicatePath         = errors.New("duplicate route path")
+	ErrDuplicateMatch        = errors.New("duplicate match expression")
+	ErrInvalidMatch          = errors.New("invalid match expression")
+	ErrMissingHandler        = errors.New("no handler registered for route")
+	ErrBulkUnsupported       = errors.New("bulk subscribe is not supported")
 )
 
-var ErrDuplicateDefaultRoute = errors.New("duplicate default route")
+// RawPayload is subscription metadata for receiving messages
+// that were published without a CloudEvent envelope.
+var RawPayload = map[string]string{
+	pubsub.MetadataRawPayload: "true",
+}
 
 // Subscribe will gather all the subscriptions from `subscribers`,
-// merge them, and pass them to `register`.
-func Subscribe(log logr.Logger, register func(subscriptions []*Subscription), subscribers ...Subscriber) {
+// merge them, validate them, and pass them to `target`.
+// Nothing is registered if any subscription is invalid.
+func Subscribe(log logr.Logger, target SubscriptionTarget, subscribers ...Subscriber) error {
+	subscriptions, err := MergeSubscriptions(subscribers...)
+	err = multierr.Append(err, ValidateSubscriptions(subscriptions, target.HasTopicEventHandler))
+	if err != nil {
+		return err
+	}
+
+	if err := target.Subscribe(subscriptions); err != nil {
+		return err
+	}
+	log.Info("Registered subscriptions", "count", len(subscriptions))
+	return nil
+}
+
+// MergeSubscriptions combines the subscriptions from `subscribers`
+// into one subscription per pubsub and topic. Subscriptions are sorted
+// by pubsub and topic, and rules are sorted by priority, so the result
+// is the same regardless of the order of `subscribers`.
+func MergeSubscriptions(subscribers ...Subscriber) ([]*Subscription, error) {
+	var errs error
 	subscriptions := make([]*Subscription, 0, 10)
 	subscriptionMap := make(map[string]*Subscription)
 
@@ -53,16 +113,85 @@ func Subscribe(log logr.Logger, register func(subscriptions []*Subscription), su
 				subscriptions = append(subscriptions, sub)
 			}
 			sub.Routes.Rules = append(sub.Routes.Rules, s.Routes.Rules...)
+			for k, v := range s.Metadata {
+				if sub.Metadata == nil {
+					sub.Metadata = make(map[string]string, len(s.Metadata))
+				}
+				sub.Metadata[k] = v
+			}
+			if s.BulkSubscribe != nil && sub.BulkSubscribe == nil {
+				sub.BulkSubscribe = s.BulkSubscribe
+			}
+			if s.DeadLetterTopic != "" {
+				if sub.DeadLetterTopic != "" && sub.DeadLetterTopic != s.DeadLetterTopic {
+					errs = multierr.Append(errs, fmt.Errorf("%w for pubsub %q topic %q: %q and %q",
+						ErrDuplicateDeadLetter, sub.PubsubName, sub.Topic, sub.DeadLetterTopic, s.DeadLetterTopic))
+				} else {
+					sub.DeadLetterTopic = s.DeadLetterTopic
+				}
+			}
 			if s.Routes.Default != "" {
-				if sub.Routes.Default != "" {
-					log.Error(ErrDuplicateDefaultRoute,
-						"Default route already exists for subscription",
-						"pubsub", sub.PubsubName, "topic", sub.Topic)
+				if sub.Routes.Default != "" && sub.Routes.Default != s.Routes.Default {
+					errs = multierr.Append(errs, fmt.Errorf("%w for pubsub %q topic %q: %q and %q",
+						ErrDuplicateDefaultRoute, sub.PubsubName, sub.Topic, sub.Routes.Default, s.Routes.Default))
+					continue
 				}
 				sub.Routes.Default = s.Routes.Default
 			}
 		}
 	}
 
-	register(subscriptions)
+	sort.SliceStable(subscriptions, func(i, j int) bool {
+		if subscriptions[i].PubsubName != subscriptions[j].PubsubName {
+			return subscriptions[i].PubsubName < subscriptions[j].PubsubName
+		}
+		return subscriptions[i].Topic < subscriptions[j].Topic
+	})
+	for _, sub := range subscriptions {
+		rules := sub.Routes.Rules
+		sort.SliceStable(rules, func(i, j int) bool {
+			return rules[i].Priority < rules[j].Priority
+		})
+	}
+
+	return subscriptions, errs
+}
+
+// ValidateSubscriptions checks for duplicate rules and invalid match expressions.
+// If `hasHandler` is not nil, it also checks that every route path has a handler.
+func ValidateSubscriptions(subscriptions []*Subscription, hasHandler func(path string) bool) error {
+	var errs error
+	for _, sub := range subscriptions {
+		paths := make(map[string]struct{}, len(sub.Routes.Rules))
+		matches := make(map[string]struct{}, len(sub.Routes.Rules))
+		for _, rule := range sub.Routes.Rules {
+			if _, ok := paths[rule.Path]; ok {
+				errs = multierr.Append(errs, fmt.Errorf("%w %q for pubsub %q topic %q",
+					ErrDuplicatePath, rule.Path, sub.PubsubName, sub.Topic))
+			}
+			paths[rule.Path] = struct{}{}
+			if _, ok := matches[rule.Match]; ok {
+				errs = multierr.Append(errs, fmt.Errorf("%w %q for pubsub %q topic %q",
+					ErrDuplicateMatch, rule.Match, sub.PubsubName, sub.Topic))
+			}
+			matches[rule.Match] = struct{}{}
+			if err := ValidateMatch(rule.Match); err != nil {
+				errs = multierr.Append(errs, fmt.Errorf("%w for path %q: %v",
+					ErrInvalidMatch, rule.Path, err))
+			}
+		}
+		if sub.Routes.Default != "" {
+			paths[sub.Routes.Default] = struct{}{}
+		}
+		if hasHandler == nil {
+			continue
+		}
+		for path := range paths {
+			if !hasHandler(path) {
+				errs = multierr.Append(errs, fmt.Errorf("%w %q for pubsub %q topic %q",
+					ErrMissingHandler, path, sub.PubsubName, sub.Topic))
+			}
+		}
+	}
+	return errs
 }
@@ -0,0 +1,72 @@
+package dapr
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type subscriber []Subscription
+
+func (s subscriber) Subscriptions() []Subscription {
+	return s
+}
+
+func topic(name string, rules ...Rule) Subscription {
+	return Subscription{
+		PubsubName: "pubsub",
+		Topic:      name,
+		Routes:     Routes{Rules: rules},
+	}
+}
+
+func TestMergeSubscriptionsPriority(t *testing.T) {
+	widgets := subscriber{
+		topic("inventory",
+			Rule{Match: `event.type == "widget"`, Path: "/widgets", Priority: 2},
+			Rule{Match: `event.type == "widget.v2"`, Path: "/widgets/v2", Priority: 1}),
+		topic("audit", Rule{Match: `event.type == "widget"`, Path: "/audit"}),
+	}
+	gadgets := subscriber{
+		topic("inventory",
+			Rule{Match: `event.type == "gadget"`, Path: "/gadgets", Priority: 2},
+			Rule{Match: `event.tenant == "acme"`, Path: "/acme"}),
+	}
+	// Rules with the same priority keep the order of their subscribers.
+	tests := []struct {
+		name        string
+		subscribers []Subscriber
+		want        []string
+	}{
+		{"widgets first", []Subscriber{widgets, gadgets}, []string{"/acme", "/widgets/v2", "/widgets", "/gadgets"}},
+		{"gadgets first", []Subscriber{gadgets, widgets}, []string{"/acme", "/widgets/v2", "/gadgets", "/widgets"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subscriptions, err := MergeSubscriptions(tt.subscribers...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(subscriptions) != 2 || subscriptions[0].Topic != "audit" || subscriptions[1].Topic != "inventory" {
+				t.Fatalf("expected the audit and inventory topics in order, got %v", subscriptions)
+			}
+			var paths []string
+			for _, rule := range subscriptions[1].Routes.Rules {
+				paths = append(paths, rule.Path)
+			}
+			if !reflect.DeepEqual(paths, tt.want) {
+				t.Errorf("expected rules %v, got %v", tt.want, paths)
+			}
+		})
+	}
+}
+
+func TestMergeSubscriptionsDuplicateDefault(t *testing.T) {
+	sub := Subscription{PubsubName: "pubsub", Topic: "inventory", Routes: Routes{Default: "/widgets"}}
+	other := sub
+	other.Routes.Default = "/gadgets"
+	_, err := MergeSubscriptions(subscriber{sub}, subscriber{other})
+	if !errors.Is(err, ErrDuplicateDefaultRoute) {
+		t.Errorf("expected %v, got %v", ErrDuplicateDefaultRoute, err)
+	}
+}
@@ -4,6 +4,54 @@ import (
 	"fmt"
 )
 
+// Error types
+const (
+	TypeInvalidArgument    = "INVALID_ARGUMENT"
+	TypeFailedPrecondition = "FAILED_PRECONDITION"
+	TypeUnauthenticated    = "UNAUTHENTICATED"
+	TypePermissionDenied   = "PERMISSION_DENIED"
+	TypeNotFound           = "NOT_FOUND"
+	TypeAlreadyExists      = "ALREADY_EXISTS"
+	TypeConflict           = "CONFLICT"
+	TypeResourceExhausted  = "RESOURCE_EXHAUSTED"
+	TypeCanceled           = "CANCELED"
+	TypeInternal           = "INTERNAL_SERVER_ERROR"
+	TypeUnavailable        = "UNAVAILABLE"
+	TypeDeadlineExceeded   = "DEADLINE_EXCEEDED"
+)
+
+// httpCodes are the HTTP status codes for each error type.
+var httpCodes = map[string]int{
+	TypeInvalidArgument:    400,
+	TypeFailedPrecondition: 412,
+	TypeUnauthenticated:    401,
+	TypePermissionDenied:   403,
+	TypeNotFound:           404,
+	TypeAlreadyExists:      409,
+	TypeConflict:           409,
+	TypeResourceExhausted:  429,
+	TypeCanceled:           499,
+	TypeInternal:           500,
+	TypeUnavailable:        503,
+	TypeDeadlineExceeded:   504,
+}
+
+// httpTypes are the error types for HTTP status codes.
+// Codes that are shared by several types use the most general one.
+var httpTypes = map[int]string{
+	400: TypeInvalidArgument,
+	401: TypeUnauthenticated,
+	403: TypePermissionDenied,
+	404: TypeNotFound,
+	409: TypeConflict,
+	412: TypeFailedPrecondition,
+	429: TypeResourceExhausted,
+	499: TypeCanceled,
+	500: TypeInternal,
+	503: TypeUnavailable,
+	504: TypeDeadlineExceeded,
+}
+
 type Error struct {
 	Type     string      `json:"type"`
 	Code     int         `json:"code"`
@@ -15,17 +63,95 @@ type Error struct {
 
 type Metadata map[string]interface{}
 
+// FieldViolation describes why a field of a request is invalid.
+type FieldViolation struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+// FieldViolations collects the invalid fields of a request
+// and is returned as the details of `InvalidArgument` errors.
+type FieldViolations []FieldViolation
+
 func Internal(err error, format string, args ...interface{}) *Error {
 	message := fmt.Sprintf(format, args...)
-	return Build("INTERNAL_SERVER_ERROR", 500, message).
+	return Build(TypeInternal, 500, message).
 		Metadata(Metadata{"error": err}).
 		Error(err).
 		Err()
 }
 
+func InvalidArgument(format string, args ...interface{}) *Error {
+	return newf(TypeInvalidArgument, format, args...)
+}
+
+// FailedPrecondition is for requests that cannot be
+// processed in the current state of the system. It is sent as
+// HTTP 412 so that it is not read back as `InvalidArgument`.
+func FailedPrecondition(format string, args ...interface{}) *Error {
+	return newf(TypeFailedPrecondition, format, args...)
+}
+
+func Unauthenticated(format string, args ...interface{}) *Error {
+	return newf(TypeUnauthenticated, format, args...)
+}
+
+func PermissionDenied(format string, args ...interface{}) *Error {
+	return newf(TypePermissionDenied, format, args...)
+}
+
 func NotFound(format string, args ...interface{}) *Error {
-	message := fmt.Sprintf(format, args...)
-	return New("NOT_FOUND", 404, message)
+	return newf(TypeNotFound, format, args...)
+}
+
+func AlreadyExists(format string, args ...interface{}) *Error {
+	return newf(TypeAlreadyExists, format, args...)
+}
+
+// Conflict is for concurrent modifications, i.e. an ETag mismatch.
+func Conflict(format string, args ...interface{}) *Error {
+	return newf(TypeConflict, format, args...)
+}
+
+func ResourceExhausted(format string, args ...interface{}) *Error {
+	return newf(TypeResourceExhausted, format, args...)
+}
+
+func Canceled(format string, args ...interface{}) *Error {
+	return newf(TypeCanceled, format, args...)
+}
+
+func Unavailable(format string, args ...interface{}) *Error {
+	return newf(TypeUnavailable, format, args...)
+}
+
+func DeadlineExceeded(format string, args ...interface{}) *Error {
+	return newf(TypeDeadlineExceeded, format, args...)
+}
+
+// FromHTTP creates an error for an HTTP status code.
+func FromHTTP(code int, format string, args ...interface{}) *Error {
+	t, ok := httpTypes[code]
+	if !ok {
+		if code >= 400 && code < 500 {
+			t = TypeInvalidArgument
+		} else {
+			t = TypeInternal
+		}
+	}
+	return New(t, code, fmt.Sprintf(format, args...))
+}
+
+// HTTPCode returns the HTTP status code for an error type.
+func HTTPCode(t string) int {
+	if code, ok := httpCodes[t]; ok {
+		return code
+	}
+	return 500
+}
+
+func newf(t string, format string, args ...interface{}) *Error {
+	return New(t, HTTPCode(t), fmt.Sprintf(format, args...))
 }
 
 func From(err error) *Error {
@@ -124,3 +250,20 @@ func (b Builder) Error(err error) Builder {
 func (b Builder) Err() *Error {
 	return b.err
 }
+
+// Add records that `field` is invalid.
+func (v *FieldViolations) Add(field, format string, args ...interface{}) {
+	*v = append(*v, FieldViolation{
+		Field:       field,
+		Description: fmt.Sprintf(format, args...),
+	})
+}
+
+// Err returns an `InvalidArgument` error with the violations as details,
+// or nil if there are no violations.
+func (v FieldViolations) Err(format string, args ...interface{}) error {
+	if len(v) == 0 {
+		return nil
+	}
+	return InvalidArgument(format, args...).WithDetails(v)
+}
@@ -0,0 +1,193 @@
+package errorz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Domain is the `google.rpc.ErrorInfo` domain of errors sent over gRPC.
+const Domain = "github.com/pkedy/golang-dapr"
+
+// grpcCodes are the gRPC status codes for each error type.
+var grpcCodes = map[string]codes.Code{
+	TypeInvalidArgument:    codes.InvalidArgument,
+	TypeFailedPrecondition: codes.FailedPrecondition,
+	TypeUnauthenticated:    codes.Unauthenticated,
+	TypePermissionDenied:   codes.PermissionDenied,
+	TypeNotFound:           codes.NotFound,
+	TypeAlreadyExists:      codes.AlreadyExists,
+	TypeConflict:           codes.Aborted,
+	TypeResourceExhausted:  codes.ResourceExhausted,
+	TypeCanceled:           codes.Canceled,
+	TypeInternal:           codes.Internal,
+	TypeUnavailable:        codes.Unavailable,
+	TypeDeadlineExceeded:   codes.DeadlineExceeded,
+}
+
+// grpcTypes are the error types for gRPC status codes.
+var grpcTypes = map[codes.Code]string{
+	codes.InvalidArgument:    TypeInvalidArgument,
+	codes.OutOfRange:         TypeInvalidArgument,
+	codes.FailedPrecondition: TypeFailedPrecondition,
+	codes.Unauthenticated:    TypeUnauthenticated,
+	codes.PermissionDenied:   TypePermissionDenied,
+	codes.NotFound:           TypeNotFound,
+	codes.AlreadyExists:      TypeAlreadyExists,
+	codes.Aborted:            TypeConflict,
+	codes.ResourceExhausted:  TypeResourceExhausted,
+	codes.Canceled:           TypeCanceled,
+	codes.Unavailable:        TypeUnavailable,
+	codes.DeadlineExceeded:   TypeDeadlineExceeded,
+}
+
+// GRPCCode returns the gRPC status code for an error type.
+// Unknown types fall back to the code for their HTTP status.
+func (e *Error) GRPCCode() codes.Code {
+	if code, ok := grpcCodes[e.Type]; ok {
+		return code
+	}
+	if t, ok := httpTypes[e.Code]; ok {
+		return grpcCodes[t]
+	}
+	return codes.Unknown
+}
+
+// GRPCStatus converts the error to a gRPC status. The type and
+// metadata are sent as `google.rpc.ErrorInfo` and field violations
+// as `google.rpc.BadRequest`, so `FromStatus` can restore them.
+func (e *Error) GRPCStatus() *status.Status {
+	st := status.New(e.GRPCCode(), e.Message)
+	info := errdetails.ErrorInfo{
+		Reason: e.Type,
+		Domain: Domain,
+	}
+	for k, v := range e.Metadata {
+		// Wrapped errors are internal and not sent to clients.
+		if _, ok := v.(error); ok || v == nil {
+			continue
+		}
+		if info.Metadata == nil {
+			info.Metadata = make(map[string]string, len(e.Metadata))
+		}
+		info.Metadata[k] = fmt.Sprint(v)
+	}
+	withDetails, err := st.WithDetails(&info)
+	if err != nil {
+		return st
+	}
+	if violations, ok := e.Details.(FieldViolations); ok {
+		var badRequest errdetails.BadRequest
+		for _, v := range violations {
+			badRequest.FieldViolations = append(badRequest.FieldViolations,
+				&errdetails.BadRequest_FieldViolation{
+					Field:       v.Field,
+					Description: v.Description,
+				})
+		}
+		if st, err := withDetails.WithDetails(&badRequest); err == nil {
+			withDetails = st
+		}
+	}
+	return withDetails
+}
+
+// ToStatus converts any error to a gRPC status. Errors that are not
+// an `*Error` or a gRPC status are internal, except for context errors.
+func ToStatus(err error) *status.Status {
+	if err == nil {
+		return nil
+	}
+	var errz *Error
+	if errors.As(err, &errz) {
+		return errz.GRPCStatus()
+	}
+	if st, ok := status.FromError(err); ok {
+		return st
+	}
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.New(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.New(codes.Canceled, err.Error())
+	}
+	return status.New(codes.Internal, err.Error())
+}
+
+// FromCode creates an error for a gRPC status code.
+func FromCode(code codes.Code, format string, args ...interface{}) *Error {
+	t, ok := grpcTypes[code]
+	if !ok {
+		t = TypeInternal
+	}
+	return newf(t, format, args...)
+}
+
+// FromStatus converts a gRPC status to an error, restoring the
+// type, metadata and field violations sent by `GRPCStatus`.
+func FromStatus(st *status.Status) *Error {
+	e := FromCode(st.Code(), "%s", st.Message())
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *errdetails.ErrorInfo:
+			// Reasons from other domains are not error types.
+			// Unknown types keep the HTTP status of the gRPC code.
+			if d.Domain == Domain && d.Reason != "" {
+				e.Type = d.Reason
+				if code, ok := httpCodes[d.Reason]; ok {
+					e.Code = code
+				}
+			}
+			if len(d.Metadata) > 0 {
+				e.Metadata = make(Metadata, len(d.Metadata))
+				for k, v := range d.Metadata {
+					e.Metadata[k] = v
+				}
+			}
+		case *errdetails.BadRequest:
+			violations := make(FieldViolations, 0, len(d.FieldViolations))
+			for _, v := range d.FieldViolations {
+				violations = append(violations, FieldViolation{
+					Field:       v.Field,
+					Description: v.Description,
+				})
+			}
+			e.Details = violations
+		}
+	}
+	return e
+}
+
+// UnaryServerInterceptor converts errors returned by handlers to
+// gRPC statuses, so services can return `*Error` values.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return resp, ToStatus(err).Err()
+		}
+		return resp, nil
+	}
+}
+
+// UnaryClientInterceptor converts gRPC status errors returned by
+// calls to `*Error` values.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{},
+		cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if err == nil {
+			return nil
+		}
+		if st, ok := status.FromError(err); ok {
+			return FromStatus(st)
+		}
+		return err
+	}
+}
@@ -0,0 +1,210 @@
+package errorz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	pb "github.com/pkedy/golang-dapr/proto/products"
+)
+
+func TestStatusRoundTrip(t *testing.T) {
+	var violations FieldViolations
+	violations.Add("id", "is required")
+	violations.Add("price", "must not be negative")
+	tests := []struct {
+		name string
+		err  *Error
+		code codes.Code
+	}{
+		{"invalid argument", violations.Err("invalid widget").(*Error), codes.InvalidArgument},
+		{"failed precondition", FailedPrecondition("widget is locked"), codes.FailedPrecondition},
+		{"not found", NotFound("widget %q not found", "w1").WithMetadata(Metadata{"id": "w1"}), codes.NotFound},
+		{"already exists", AlreadyExists("widget %q already exists", "w1"), codes.AlreadyExists},
+		{"conflict", Conflict("widget was modified"), codes.Aborted},
+		{"unavailable", Unavailable("state store is down"), codes.Unavailable},
+		{"custom type", New("WIDGET_LOCKED", 409, "widget is locked"), codes.Aborted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := tt.err.GRPCStatus()
+			if st.Code() != tt.code {
+				t.Errorf("expected code %v, got %v", tt.code, st.Code())
+			}
+			got := FromStatus(st)
+			if !reflect.DeepEqual(got, tt.err) {
+				t.Errorf("expected %+v, got %+v", tt.err, got)
+			}
+		})
+	}
+}
+
+func TestStatusMetadata(t *testing.T) {
+	cause := errors.New("connection refused")
+	err := Internal(cause, "could not save widget")
+	err.Metadata["id"] = "w1"
+	err.Metadata["attempts"] = 3
+	got := FromStatus(err.GRPCStatus())
+	if want := (Metadata{"id": "w1", "attempts": "3"}); !reflect.DeepEqual(got.Metadata, want) {
+		t.Errorf("expected metadata %v without the wrapped error, got %v", want, got.Metadata)
+	}
+	if got.Err != nil {
+		t.Errorf("expected the wrapped error not to be sent, got %v", got.Err)
+	}
+}
+
+func TestFromStatusOtherDomain(t *testing.T) {
+	st, err := status.New(codes.NotFound, "no such table").WithDetails(&errdetails.ErrorInfo{
+		Reason: "TABLE_NOT_FOUND",
+		Domain: "example.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := FromStatus(st); got.Type != TypeNotFound || got.Code != 404 {
+		t.Errorf("expected the type of the status code, got %+v", got)
+	}
+}
+
+func TestToStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code codes.Code
+	}{
+		{"error", errors.New("boom"), codes.Internal},
+		{"wrapped error", fmt.Errorf("get widget: %w", NotFound("widget not found")), codes.NotFound},
+		{"deadline", fmt.Errorf("get widget: %w", context.DeadlineExceeded), codes.DeadlineExceeded},
+		{"canceled", context.Canceled, codes.Canceled},
+		{"status", status.Error(codes.PermissionDenied, "go away"), codes.PermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := ToStatus(tt.err).Code(); code != tt.code {
+				t.Errorf("expected code %v, got %v", tt.code, code)
+			}
+		})
+	}
+	if st := ToStatus(nil); st != nil {
+		t.Errorf("expected no status, got %v", st)
+	}
+}
+
+func TestFromHTTP(t *testing.T) {
+	tests := []struct {
+		code int
+		t    string
+	}{
+		{400, TypeInvalidArgument},
+		{404, TypeNotFound},
+		{409, TypeConflict},
+		{412, TypeFailedPrecondition},
+		{418, TypeInvalidArgument},
+		{502, TypeInternal},
+		{503, TypeUnavailable},
+	}
+	for _, tt := range tests {
+		err := FromHTTP(tt.code, "status %d", tt.code)
+		if err.Type != tt.t || err.Code != tt.code {
+			t.Errorf("expected %s for %d, got %s and %d", tt.t, tt.code, err.Type, err.Code)
+		}
+	}
+	for typ, code := range httpCodes {
+		if typ == TypeAlreadyExists {
+			// 409 is read back as the more general `Conflict`.
+			continue
+		}
+		if got := FromHTTP(code, "").Type; got != typ {
+			t.Errorf("expected %s to round trip through %d, got %s", typ, code, got)
+		}
+	}
+}
+
+// productsServer fails every call with `err`.
+type productsServer struct {
+	pb.UnimplementedProductsServer
+	err error
+}
+
+func (s *productsServer) CreateProduct(context.Context, *pb.Product) (*emptypb.Empty, error) {
+	return nil, s.err
+}
+
+// productsClient returns a client with `UnaryClientInterceptor` for a
+// server with `UnaryServerInterceptor` that fails every call with `err`.
+func productsClient(t *testing.T, err error) pb.ProductsClient {
+	t.Helper()
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(grpc.UnaryInterceptor(UnaryServerInterceptor()))
+	pb.RegisterProductsServer(server, &productsServer{err: err})
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, dialErr := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(UnaryClientInterceptor()),
+	)
+	if dialErr != nil {
+		t.Fatal(dialErr)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return pb.NewProductsClient(conn)
+}
+
+func TestInterceptors(t *testing.T) {
+	var violations FieldViolations
+	violations.Add("id", "is required")
+	tests := []struct {
+		name string
+		err  error
+		want *Error
+	}{
+		{
+			name: "field violations",
+			err:  violations.Err("invalid product"),
+			want: InvalidArgument("invalid product").WithDetails(violations),
+		},
+		{
+			name: "wrapped error",
+			err:  fmt.Errorf("create product: %w", AlreadyExists("product %q already exists", "p1")),
+			want: AlreadyExists("product %q already exists", "p1"),
+		},
+		{
+			name: "status",
+			err:  status.Error(codes.Unavailable, "store is down"),
+			want: Unavailable("store is down"),
+		},
+		{
+			name: "error",
+			err:  errors.New("boom"),
+			want: New(TypeInternal, 500, "boom"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := productsClient(t, tt.err)
+			_, err := client.CreateProduct(context.Background(), &pb.Product{Id: "p1"})
+			var got *Error
+			if !errors.As(err, &got) {
+				t.Fatalf("expected an *Error, got %T: %v", err, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
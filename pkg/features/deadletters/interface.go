@@ -0,0 +1,16 @@
+package deadletters
+
+import (
+	"context"
+
+	"github.com/pkedy/golang-dapr/pkg/dapr"
+)
+
+type (
+	// Store keeps dead-lettered events until they are re-driven.
+	Store interface {
+		Load(ctx context.Context, id string) (*dapr.CloudEvent, error)
+		Save(ctx context.Context, event *dapr.CloudEvent) error
+		Delete(ctx context.Context, id string) error
+	}
+)
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+
+	"github.com/pkedy/golang-dapr/pkg/components/state"
+	"github.com/pkedy/golang-dapr/pkg/dapr"
+	"github.com/pkedy/golang-dapr/pkg/errorz"
+)
+
+type Repository struct {
+	log         logr.Logger
+	stateClient state.Store
+	store       string
+}
+
+func New(log logr.Logger, stateClient state.Store, store string) *Repository {
+	return &Repository{
+		log:         log,
+		stateClient: stateClient,
+		store:       store,
+	}
+}
+
+func (r *Repository) Save(ctx context.Context, event *dapr.CloudEvent) error {
+	r.log.Info("Saving dead letter", "id", event.ID, "type", event.Type, "source", event.Source)
+	if err := r.stateClient.SetState(ctx, r.store, []state.Item{{
+		Key:   "deadletter:" + event.ID,
+		Value: event,
+	}}); err != nil {
+		return errorz.From(err).WithMessage("could not save dead letter %q", event.ID)
+	}
+	return nil
+}
+
+func (r *Repository) Load(ctx context.Context, id string) (*dapr.CloudEvent, error) {
+	r.log.Info("Loading dead letter", "id", id)
+	var event dapr.CloudEvent
+	if err := r.stateClient.GetState(ctx, r.store, "deadletter:"+id, &event); err != nil {
+		err := errorz.From(err)
+		if err.Code == 404 {
+			return nil, err.WithMessage("dead letter %q not found", id)
+		}
+		return nil, err.WithMessage("could not load dead letter %q", id)
+	}
+
+	return &event, nil
+}
+
+func (r *Repository) Delete(ctx context.Context, id string) error {
+	r.log.Info("Deleting dead letter", "id", id)
+	if err := r.stateClient.DeleteState(ctx, r.store, "deadletter:"+id, ""); err != nil {
+		return errorz.From(err).WithMessage("could not delete dead letter %q", id)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/pkedy/golang-dapr/pkg/components/pubsub"
+	"github.com/pkedy/golang-dapr/pkg/dapr"
+	"github.com/pkedy/golang-dapr/pkg/errorz"
+	"github.com/pkedy/golang-dapr/pkg/features/deadletters"
+)
+
+const (
+	pubsubName      = "pubsub"
+	topicInventory  = "inventory"
+	topicDeadLetter = "inventory-deadletter"
+)
+
+type (
+	// Service keeps events that could not be delivered on the
+	// inventory topic so that operators can inspect and re-drive them.
+	Service struct {
+		*dapr.EventHandlers
+		log       logr.Logger
+		store     deadletters.Store
+		publisher pubsub.Publisher
+	}
+)
+
+func New(log logr.Logger, store deadletters.Store, publisher pubsub.Publisher) *Service {
+	s := &Service{
+		log:       log,
+		store:     store,
+		publisher: publisher,
+	}
+	s.EventHandlers = dapr.NewEventHandlers(log,
+		dapr.HandleDeadLetter(pubsubName, topicDeadLetter, "/inventory.deadletter", s.Save),
+	)
+	return s
+}
+
+// SERVICE OPERATIONS
+
+func (s *Service) RegisterService(app *fiber.App) {
+	app.Get("/v1/deadletters/:id", func(c *fiber.Ctx) error {
+		event, err := s.store.Load(c.Context(), c.Params("id"))
+		return response(c, event, err)
+	})
+	app.Post("/v1/deadletters/:id/redrive", func(c *fiber.Ctx) error {
+		if err := s.Redrive(c.Context(), c.Params("id")); err != nil {
+			return err
+		}
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+}
+
+// Redrive publishes the original event to the inventory topic again
+// and removes it from the store.
+func (s *Service) Redrive(ctx context.Context, id string) error {
+	event, err := s.store.Load(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.publisher.PublishEvent(ctx, pubsubName, topicInventory, event,
+		pubsub.WithCloudEvent()); err != nil {
+		return errorz.From(err).
+			WithMessage("could not re-drive dead letter %q", id)
+	}
+	return s.store.Delete(ctx, id)
+}
+
+func response(c *fiber.Ctx, val interface{}, err error) error {
+	if err != nil {
+		return err
+	}
+	return c.JSON(val)
+}
+
+// EVENT HANDLERS
+
+func (s *Service) Save(ctx context.Context, event *dapr.CloudEvent) error {
+	s.log.Info("Received dead letter", "id", event.ID, "type", event.Type, "source", event.Source)
+	return s.store.Save(ctx, event)
+}
@@ -0,0 +1,88 @@
+// Package features creates the services of the inventory app so that
+// every command registers the same event handlers and subscriptions.
+package features
+
+import (
+	"github.com/dapr/go-sdk/service/common"
+	"github.com/go-logr/logr"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/multierr"
+
+	"github.com/pkedy/golang-dapr/pkg/components/pubsub"
+	"github.com/pkedy/golang-dapr/pkg/dapr"
+	"github.com/pkedy/golang-dapr/pkg/features/deadletters"
+	deadletters_service "github.com/pkedy/golang-dapr/pkg/features/deadletters/service"
+	"github.com/pkedy/golang-dapr/pkg/features/gadgets"
+	gadgets_service "github.com/pkedy/golang-dapr/pkg/features/gadgets/service"
+	"github.com/pkedy/golang-dapr/pkg/features/products"
+	products_service "github.com/pkedy/golang-dapr/pkg/features/products/service"
+	"github.com/pkedy/golang-dapr/pkg/features/widgets"
+	widgets_service "github.com/pkedy/golang-dapr/pkg/features/widgets/service"
+)
+
+type (
+	// Dependencies are the stores and clients used by the services.
+	// Services created without them can only list their subscriptions.
+	Dependencies struct {
+		Widgets      widgets.Store
+		WidgetOutbox widgets.Outbox
+		Gadgets      gadgets.Store
+		Products     products.Store
+		DeadLetters  deadletters.Store
+		Publisher    pubsub.Publisher
+		// Dedup (if not nil) skips redelivered events in every handler.
+		Dedup *dapr.Dedup
+	}
+
+	Services struct {
+		Widgets     *widgets_service.Service
+		Gadgets     *gadgets_service.Service
+		Products    *products_service.Service
+		DeadLetters *deadletters_service.Service
+	}
+)
+
+func NewServices(log logr.Logger, deps Dependencies) *Services {
+	s := &Services{
+		Widgets:     widgets_service.New(log, deps.Widgets, deps.WidgetOutbox),
+		Gadgets:     gadgets_service.New(log, deps.Gadgets),
+		Products:    products_service.New(log, deps.Products),
+		DeadLetters: deadletters_service.New(log, deps.DeadLetters, deps.Publisher),
+	}
+	for _, e := range []*dapr.EventHandlers{
+		s.Widgets.EventHandlers,
+		s.Gadgets.EventHandlers,
+		s.Products.EventHandlers,
+		s.DeadLetters.EventHandlers,
+	} {
+		e.WithDedup(deps.Dedup)
+	}
+	return s
+}
+
+// Subscribers returns every service that subscribes to topics.
+func (s *Services) Subscribers() []dapr.Subscriber {
+	return []dapr.Subscriber{s.Widgets, s.Gadgets, s.Products, s.DeadLetters}
+}
+
+func (s *Services) RegisterService(app *fiber.App) {
+	dapr.RegisterServices(app, s.Widgets, s.Gadgets, s.Products, s.DeadLetters)
+}
+
+func (s *Services) RegisterEventHandlers(app *fiber.App) {
+	dapr.RegisterEventHandlers(app, s.Widgets, s.Gadgets, s.Products, s.DeadLetters)
+}
+
+func (s *Services) RegisterTopicEventHandlers(register dapr.RegisterEventHandler) {
+	for _, r := range []dapr.HandlerRegistrar{s.Widgets, s.Gadgets, s.Products, s.DeadLetters} {
+		r.RegisterTopicEventHandlers(register)
+	}
+}
+
+func (s *Services) RegisterTopicEventHandlersSDK(service common.Service) error {
+	return multierr.Combine(
+		s.Widgets.RegisterTopicEventHandlersSDK(service),
+		s.Gadgets.RegisterTopicEventHandlersSDK(service),
+		s.Products.RegisterTopicEventHandlersSDK(service),
+		s.DeadLetters.RegisterTopicEventHandlersSDK(service))
+}
@@ -2,17 +2,40 @@ package gadgets
 
 import (
 	"context"
+
+	"github.com/pkedy/golang-dapr/pkg/pagination"
+	"github.com/pkedy/golang-dapr/pkg/validation"
 )
 
 type (
 	Store interface {
 		Load(ctx context.Context, id string) (*Gadget, error)
+		// Create saves a new gadget. It returns an `errorz.AlreadyExists`
+		// error if a gadget with the same ID exists.
+		Create(ctx context.Context, gadget *Gadget) error
 		Save(ctx context.Context, gadget *Gadget) error
+		Delete(ctx context.Context, id string) error
+		List(ctx context.Context, filter Filter) (*Page, error)
+	}
+
+	// Filter narrows the gadgets returned by `List`.
+	// Gadgets can be sorted by price.
+	Filter struct {
+		pagination.Request
+		MaxPrice *float64
 	}
 
+	Page = pagination.Page[*Gadget]
+
 	Gadget struct {
 		ID          string  `json:"id"`
 		Description string  `json:"description"`
 		Price       float64 `json:"price"`
 	}
 )
+
+// Validate returns an `errorz.InvalidArgument` error
+// with the fields of the gadget that are invalid.
+func (g *Gadget) Validate() error {
+	return validation.Item("gadget", g.ID, g.Description, g.Price)
+}
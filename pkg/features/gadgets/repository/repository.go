@@ -2,44 +2,121 @@ package repository
 
 import (
 	"context"
+	"strings"
 
 	"github.com/go-logr/logr"
 
 	"github.com/pkedy/golang-dapr/pkg/components/state"
 	"github.com/pkedy/golang-dapr/pkg/errorz"
 	"github.com/pkedy/golang-dapr/pkg/features/gadgets"
+	"github.com/pkedy/golang-dapr/pkg/pagination"
 )
 
+const (
+	keyPrefix = "gadget:"
+	// docType is saved with each gadget so queries only return gadgets
+	// and not other state (i.e. processed events) in the shared store.
+	docType = "gadget"
+	// queryIndex is the name of the `queryIndexes` entry in the component.
+	queryIndex = "gadgets"
+	// redisSetFailed starts the error Redis returns when a write loses to a concurrent one.
+	redisSetFailed = "failed to set key "
+)
+
+// jsonContent saves gadgets as JSON documents so the state store can query them.
+// Redis requires it on every request for a key saved with it.
+var jsonContent = state.WithMetadata("contentType", "application/json")
+
 type Repository struct {
 	log         logr.Logger
 	stateClient state.Store
 	store       string
+	saveOptions []state.Option
 }
 
-func New(log logr.Logger, stateClient state.Store, store string) *Repository {
+// document is the stored form of a gadget.
+type document struct {
+	Type string `json:"type"`
+	*gadgets.Gadget
+}
+
+// New creates a gadget repository. `saveOptions` are applied when
+// gadgets are saved (e.g. `state.WithTTL` for gadgets that expire).
+func New(log logr.Logger, stateClient state.Store, store string, saveOptions ...state.Option) *Repository {
 	return &Repository{
 		log:         log,
 		stateClient: stateClient,
 		store:       store,
+		saveOptions: append([]state.Option{jsonContent}, saveOptions...),
 	}
 }
 
+// Create saves the gadget only if it does not exist yet.
+func (r *Repository) Create(ctx context.Context, gadget *gadgets.Gadget) error {
+	r.log.Info("Creating gadget state", "gadget", gadget)
+	key := keyPrefix + gadget.ID
+
+	opts := append(r.saveOptions[:len(r.saveOptions):len(r.saveOptions)],
+		state.WithConcurrency(state.ConcurrencyFirstWrite))
+	if err := r.stateClient.SetState(ctx, r.store, []state.Item{{
+		Key:   key,
+		Value: document{Type: docType, Gadget: gadget},
+	}}, opts...); err != nil {
+		if isConflict(err) {
+			return errorz.AlreadyExists("gadget %q already exists", gadget.ID).WithError(err)
+		}
+		return errorz.From(err).WithMessage("could not create gadget %q", gadget.ID)
+	}
+	return nil
+}
+
 func (r *Repository) Save(ctx context.Context, gadget *gadgets.Gadget) error {
 	r.log.Info("Saving gadget state", "gadget", gadget)
-	if err := r.stateClient.SetState(ctx, r.store, state.Item{
-		Key:   "gadget:" + gadget.ID,
-		Value: &gadget,
-	}); err != nil {
-		return errorz.From(err).
-			WithMessage("could not save gadget %q", gadget.ID)
+	key := keyPrefix + gadget.ID
+
+	// Read the current ETag so the write below only succeeds
+	// if no other event has updated the gadget in the meantime.
+	var existing gadgets.Gadget
+	opts := r.saveOptions
+	etag, err := r.stateClient.GetStateWithETag(ctx, r.store, key, &existing,
+		jsonContent, state.WithConsistency(state.ConsistencyStrong))
+	if err != nil {
+		if err := errorz.From(err); err.Code != 404 {
+			return err.WithMessage("could not load gadget %q", gadget.ID)
+		}
+		// There is no ETag yet, so only the first write may create the gadget.
+		opts = append(opts[:len(opts):len(opts)], state.WithConcurrency(state.ConcurrencyFirstWrite))
+	} else if existing == *gadget {
+		return nil
+	}
+
+	if err := r.stateClient.SetState(ctx, r.store, []state.Item{{
+		Key:   key,
+		Value: document{Type: docType, Gadget: gadget},
+		ETag:  etag,
+	}}, opts...); err != nil {
+		if isConflict(err) {
+			return errorz.Conflict("gadget %q was modified concurrently", gadget.ID).WithError(err)
+		}
+		return errorz.From(err).WithMessage("could not save gadget %q", gadget.ID)
 	}
 	return nil
 }
 
+// isConflict returns true if a write failed because of a concurrent write.
+// Dapr reports ETag mismatches as conflicts, but a lost first write without
+// an ETag arrives as an internal error from Redis. Its message repeats
+// `redisSetFailed` (the client wraps the script's error), unlike other
+// failures to set the key such as a lost connection.
+func isConflict(err error) bool {
+	return errorz.From(err).Code == 409 ||
+		strings.Count(err.Error(), redisSetFailed) > 1
+}
+
 func (r *Repository) Load(ctx context.Context, id string) (*gadgets.Gadget, error) {
 	r.log.Info("Loading gadget state", "id", id)
 	var gadget gadgets.Gadget
-	if err := r.stateClient.GetState(ctx, r.store, "gadget:"+id, &gadget); err != nil {
+	if err := r.stateClient.GetState(ctx, r.store, keyPrefix+id, &gadget, jsonContent); err != nil {
 		err := errorz.From(err)
 		if err.Code == 404 {
 			return nil, err.WithMessage("gadget %q not found", id)
@@ -49,3 +126,84 @@ func (r *Repository) Load(ctx context.Context, id string) (*gadgets.Gadget, erro
 
 	return &gadget, nil
 }
+
+// Delete removes the gadget, unless it was modified since it was read.
+func (r *Repository) Delete(ctx context.Context, id string) error {
+	r.log.Info("Deleting gadget state", "id", id)
+	key := keyPrefix + id
+
+	var existing gadgets.Gadget
+	etag, err := r.stateClient.GetStateWithETag(ctx, r.store, key, &existing,
+		jsonContent, state.WithConsistency(state.ConsistencyStrong))
+	if err != nil {
+		err := errorz.From(err)
+		if err.Code == 404 {
+			return err.WithMessage("gadget %q not found", id)
+		}
+		return err.WithMessage("could not load gadget %q", id)
+	}
+
+	if err := r.stateClient.DeleteState(ctx, r.store, key, etag); err != nil {
+		err := errorz.From(err)
+		if err.Code == 409 {
+			return err.WithMessage("gadget %q was modified concurrently", id)
+		}
+		return err.WithMessage("could not delete gadget %q", id)
+	}
+	return nil
+}
+
+// List queries gadgets by price. The state query API only supports
+// equality, so `MaxPrice` is applied while scanning gadgets in ascending
+// price order and the listing stops at the first gadget above it.
+func (r *Repository) List(ctx context.Context, filter gadgets.Filter) (*gadgets.Page, error) {
+	r.log.Info("Querying gadget state", "filter", filter)
+	query := state.NewQuery(state.Eq("type", docType))
+	if filter.Sort.Field == "price" || filter.MaxPrice != nil {
+		order := state.OrderAsc
+		if filter.Sort.Desc() {
+			if filter.MaxPrice != nil {
+				return nil, errorz.InvalidArgument("maxPrice requires gadgets sorted by ascending price")
+			}
+			order = state.OrderDesc
+		}
+		query.SortBy("price", order)
+	}
+	query.Limit(filter.PageLimit())
+	// The state store has its own continuation token.
+	if filter.Cursor != nil {
+		query.Continue(filter.Cursor.Token)
+	}
+
+	result, err := r.stateClient.Query(ctx, r.store, query,
+		jsonContent, state.WithMetadata("queryIndexName", queryIndex))
+	if err != nil {
+		return nil, errorz.From(err).
+			WithMessage("could not query gadgets")
+	}
+
+	page := gadgets.Page{
+		Items: make([]*gadgets.Gadget, 0, len(result.Results)),
+	}
+	token := result.Token
+	for i := range result.Results {
+		item := &result.Results[i]
+		var gadget gadgets.Gadget
+		if err := item.Decode(&gadget); err != nil {
+			return nil, errorz.Internal(err, "could not decode gadget %q", item.Key)
+		}
+		if filter.MaxPrice != nil && gadget.Price > *filter.MaxPrice {
+			token = ""
+			break
+		}
+		page.Items = append(page.Items, &gadget)
+	}
+	if token != "" {
+		page.Next = (&pagination.Cursor{
+			Sort:  filter.Sort,
+			Token: token,
+		}).Encode()
+	}
+
+	return &page, nil
+}
@@ -0,0 +1,225 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/pkedy/golang-dapr/pkg/components/state"
+	"github.com/pkedy/golang-dapr/pkg/errorz"
+	"github.com/pkedy/golang-dapr/pkg/features/gadgets"
+	"github.com/pkedy/golang-dapr/pkg/pagination"
+)
+
+// fakeStore keeps state in memory. Calls that are not
+// implemented panic through the nil embedded `state.Store`.
+type fakeStore struct {
+	state.Store
+	values  map[string][]byte
+	setErr  error
+	setOpts state.Options
+
+	// Query returns `results` and records its arguments.
+	results   state.QueryResult
+	query     *state.Query
+	queryOpts state.Options
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{values: make(map[string][]byte)}
+}
+
+func (f *fakeStore) GetState(ctx context.Context, store string, key string, target interface{}, opts ...state.Option) error {
+	_, err := f.GetStateWithETag(ctx, store, key, target, opts...)
+	return err
+}
+
+func (f *fakeStore) GetStateWithETag(ctx context.Context, store string, key string, target interface{}, opts ...state.Option) (string, error) {
+	data, ok := f.values[key]
+	if !ok {
+		return "", errorz.NotFound("key %q not found", key)
+	}
+	return "1", json.Unmarshal(data, target)
+}
+
+func (f *fakeStore) SetState(ctx context.Context, store string, items []state.Item, opts ...state.Option) error {
+	f.setOpts = state.NewOptions(opts...)
+	if f.setErr != nil {
+		return f.setErr
+	}
+	for _, item := range items {
+		data, err := json.Marshal(item.Value)
+		if err != nil {
+			return err
+		}
+		f.values[item.Key] = data
+	}
+	return nil
+}
+
+func (f *fakeStore) Query(ctx context.Context, store string, query *state.Query, opts ...state.Option) (*state.QueryResult, error) {
+	f.query = query
+	f.queryOpts = state.NewOptions(opts...)
+	return &f.results, nil
+}
+
+// setResults makes the store return gadgets with `prices` from queries.
+func (f *fakeStore) setResults(token string, prices ...float64) {
+	f.results = state.QueryResult{Token: token}
+	for i, price := range prices {
+		id := strconv.Itoa(i + 1)
+		data, _ := json.Marshal(document{
+			Type:   docType,
+			Gadget: &gadgets.Gadget{ID: id, Price: price},
+		})
+		f.results.Results = append(f.results.Results, state.BulkItem{Key: keyPrefix + id, Value: data})
+	}
+}
+
+func TestSaveNewGadget(t *testing.T) {
+	store := newFakeStore()
+	r := New(logr.Discard(), store, "statestore", state.WithTTL(time.Minute))
+	gadget := gadgets.Gadget{ID: "1", Description: "Gadget", Price: 1}
+	if err := r.Save(context.Background(), &gadget); err != nil {
+		t.Fatal(err)
+	}
+	if store.setOpts.Concurrency != state.ConcurrencyFirstWrite {
+		t.Errorf("expected first-write concurrency, got %q", store.setOpts.Concurrency)
+	}
+	if ttl := store.setOpts.Metadata[state.MetadataTTL]; ttl != "60" {
+		t.Errorf("expected TTL of 60 seconds, got %q", ttl)
+	}
+	loaded, err := r.Load(context.Background(), "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *loaded != gadget {
+		t.Errorf("expected %v, got %v", gadget, *loaded)
+	}
+}
+
+func TestSaveErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		conflict bool
+	}{
+		{"etag mismatch", errorz.Conflict("could not save state: etag mismatch"), true},
+		{"lost first write", errorz.Internal(errors.New(
+			"failed saving state in state store statestore: failed to set key inventory||gadget:1: "+
+				"ERR user_script:12: failed to set key inventory||gadget:1"), "could not save state"), true},
+		{"connection", errorz.Internal(errors.New(
+			"failed saving state in state store statestore: failed to set key inventory||gadget:1: "+
+				"dial tcp 127.0.0.1:6379: connect: connection refused"), "could not save state"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newFakeStore()
+			store.setErr = tt.err
+			r := New(logr.Discard(), store, "statestore")
+			err := r.Save(context.Background(), &gadgets.Gadget{ID: "1"})
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if conflict := errorz.From(err).Code == 409; conflict != tt.conflict {
+				t.Errorf("expected conflict to be %t, got %v", tt.conflict, err)
+			}
+
+			// The same errors mean that a created gadget already exists.
+			err = r.Create(context.Background(), &gadgets.Gadget{ID: "1"})
+			if store.setOpts.Concurrency != state.ConcurrencyFirstWrite {
+				t.Errorf("expected first-write concurrency, got %q", store.setOpts.Concurrency)
+			}
+			if exists := errorz.From(err).Type == errorz.TypeAlreadyExists; exists != tt.conflict {
+				t.Errorf("expected already exists to be %t, got %v", tt.conflict, err)
+			}
+		})
+	}
+}
+
+func TestList(t *testing.T) {
+	store := newFakeStore()
+	store.setResults("next", 1, 2, 3)
+	r := New(logr.Discard(), store, "statestore")
+	sort := pagination.Sort{Field: "price", Order: pagination.OrderDesc}
+	filter := gadgets.Filter{Request: pagination.Request{
+		Limit:  3,
+		Sort:   sort,
+		Cursor: &pagination.Cursor{Sort: sort, Token: "previous"},
+	}}
+	page, err := r.List(context.Background(), filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Items) != 3 {
+		t.Errorf("expected 3 gadgets, got %d", len(page.Items))
+	}
+	next, err := pagination.DecodeCursor(page.Next)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.Token != "next" || next.Sort != sort {
+		t.Errorf("expected a cursor with the next token and sort, got %+v", next)
+	}
+	query := store.query
+	if query.Filter["EQ"].(map[string]interface{})["type"] != docType {
+		t.Errorf("expected a filter on the document type, got %v", query.Filter)
+	}
+	if len(query.Sort) != 1 || query.Sort[0] != (state.Sort{Key: "price", Order: state.OrderDesc}) {
+		t.Errorf("expected gadgets sorted by descending price, got %v", query.Sort)
+	}
+	if query.Page.Limit != 3 || query.Page.Token != "previous" {
+		t.Errorf("expected the limit and token to be passed, got %+v", query.Page)
+	}
+	if index := store.queryOpts.Metadata["queryIndexName"]; index != queryIndex {
+		t.Errorf("expected the %q query index, got %q", queryIndex, index)
+	}
+}
+
+func TestListMaxPrice(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxPrice float64
+		count    int
+		next     bool
+	}{
+		{"all below", 5, 3, true},
+		{"truncated", 2, 2, false},
+		{"none below", 0.5, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newFakeStore()
+			store.setResults("next", 1, 2, 3)
+			r := New(logr.Discard(), store, "statestore")
+			page, err := r.List(context.Background(), gadgets.Filter{MaxPrice: &tt.maxPrice})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(page.Items) != tt.count || (page.Next != "") != tt.next {
+				t.Errorf("expected %d gadgets and next to be %t, got %d and %q",
+					tt.count, tt.next, len(page.Items), page.Next)
+			}
+			if sort := store.query.Sort; len(sort) != 1 || sort[0] != (state.Sort{Key: "price", Order: state.OrderAsc}) {
+				t.Errorf("expected gadgets sorted by ascending price, got %v", sort)
+			}
+		})
+	}
+}
+
+func TestListMaxPriceDescending(t *testing.T) {
+	maxPrice := 2.0
+	r := New(logr.Discard(), newFakeStore(), "statestore")
+	_, err := r.List(context.Background(), gadgets.Filter{
+		Request:  pagination.Request{Sort: pagination.Sort{Field: "price", Order: pagination.OrderDesc}},
+		MaxPrice: &maxPrice,
+	})
+	if code := errorz.From(err).Code; code != 400 {
+		t.Errorf("expected 400, got %v", err)
+	}
+}
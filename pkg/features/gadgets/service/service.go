@@ -2,38 +2,95 @@ package service
 
 import (
 	"context"
-	"encoding/json"
+	"strconv"
 
-	pb "github.com/dapr/dapr/pkg/proto/runtime/v1"
-	"github.com/dapr/go-sdk/service/common"
 	"github.com/go-logr/logr"
 	"github.com/gofiber/fiber/v2"
 
 	"github.com/pkedy/golang-dapr/pkg/dapr"
+	"github.com/pkedy/golang-dapr/pkg/errorz"
 	"github.com/pkedy/golang-dapr/pkg/features/gadgets"
+	"github.com/pkedy/golang-dapr/pkg/pagination"
 )
 
 type (
 	Service struct {
+		*dapr.EventHandlers
 		log   logr.Logger
 		store gadgets.Store
 	}
 )
 
 func New(log logr.Logger, store gadgets.Store) *Service {
-	return &Service{
+	s := &Service{
 		log:   log,
 		store: store,
 	}
+	s.EventHandlers = dapr.NewEventHandlers(log,
+		dapr.Handle("pubsub", "inventory", dapr.Rule{
+			Match: `event.type == "gadget.v1"`,
+			Path:  "/gadgets.v1",
+		}, s.Save).WithPriority(2).WithDeadLetterTopic("inventory-deadletter"),
+	)
+	return s
 }
 
 // SERVICE OPERATIONS
 
 func (s *Service) RegisterService(app *fiber.App) {
+	app.Get("/v1/gadgets", func(c *fiber.Ctx) error {
+		req, err := pagination.FromQuery(c, "price")
+		if err != nil {
+			return err
+		}
+		filter := gadgets.Filter{
+			Request: req,
+		}
+		if maxPrice := c.Query("maxPrice"); maxPrice != "" {
+			price, err := strconv.ParseFloat(maxPrice, 64)
+			if err != nil {
+				return errorz.InvalidArgument("invalid maxPrice %q", maxPrice)
+			}
+			filter.MaxPrice = &price
+		}
+		page, err := s.store.List(c.Context(), filter)
+		return response(c, page, err)
+	})
 	app.Get("/v1/gadgets/:id", func(c *fiber.Ctx) error {
 		gadget, err := s.store.Load(c.Context(), c.Params("id"))
 		return response(c, gadget, err)
 	})
+	app.Post("/v1/gadgets", func(c *fiber.Ctx) error {
+		var gadget gadgets.Gadget
+		if err := c.BodyParser(&gadget); err != nil {
+			return errorz.InvalidArgument("invalid gadget: %v", err)
+		}
+		if err := gadget.Validate(); err != nil {
+			return err
+		}
+		if err := s.store.Create(c.Context(), &gadget); err != nil {
+			return err
+		}
+		return response(c.Status(fiber.StatusCreated), &gadget, nil)
+	})
+	app.Put("/v1/gadgets/:id", func(c *fiber.Ctx) error {
+		var gadget gadgets.Gadget
+		if err := c.BodyParser(&gadget); err != nil {
+			return errorz.InvalidArgument("invalid gadget: %v", err)
+		}
+		gadget.ID = c.Params("id")
+		if err := gadget.Validate(); err != nil {
+			return err
+		}
+		err := s.store.Save(c.Context(), &gadget)
+		return response(c, &gadget, err)
+	})
+	app.Delete("/v1/gadgets/:id", func(c *fiber.Ctx) error {
+		if err := s.store.Delete(c.Context(), c.Params("id")); err != nil {
+			return err
+		}
+		return c.SendStatus(fiber.StatusNoContent)
+	})
 }
 
 func response(c *fiber.Ctx, val interface{}, err error) error {
@@ -46,79 +103,6 @@ func response(c *fiber.Ctx, val interface{}, err error) error {
 
 // EVENT HANDLERS
 
-func (s *Service) Subscriptions() []dapr.Subscription {
-	return []dapr.Subscription{
-		{
-			PubsubName: "pubsub",
-			Topic:      "inventory",
-			Routes: dapr.Routes{
-				Rules: []dapr.Rule{
-					{
-						Match: `event.type == "gadget.v1"`,
-						Path:  "/gadgets.v1",
-					},
-				},
-			},
-		},
-	}
-}
-
-// HTTP
-
-func (s *Service) RegisterEventHandlers(app *fiber.App) {
-	app.Post("/gadgets.v1", s.SaveHTTP)
-}
-
-func (s *Service) SaveHTTP(c *fiber.Ctx) error {
-	var gadget gadgets.Gadget
-	if err := dapr.DecodeCloudEvent(c, nil, &gadget); err != nil {
-		return err
-	}
-	if err := s.store.Save(c.Context(), &gadget); err != nil {
-		return err
-	}
-	return c.SendString("OK")
-}
-
-// gRPC
-
-func (s *Service) RegisterTopicEventHandlers(register dapr.RegisterEventHandler) {
-	register("/gadgets.v1", s.SaveGRPC)
-}
-
-func (s *Service) SaveGRPC(ctx context.Context, in *pb.TopicEventRequest) (*pb.TopicEventResponse, error) {
-	var gadget gadgets.Gadget
-	if err := json.Unmarshal(in.Data, &gadget); err != nil {
-		return nil, err
-	}
-	if err := s.store.Save(ctx, &gadget); err != nil {
-		return nil, err
-	}
-
-	return &pb.TopicEventResponse{
-		Status: pb.TopicEventResponse_SUCCESS,
-	}, nil
-}
-
-// SDK
-
-func (s *Service) RegisterTopicEventHandlersSDK(service common.Service) error {
-	return service.AddTopicEventHandler(&common.Subscription{
-		PubsubName: "pubsub",
-		Topic:      "inventory",
-		Match:      `event.type == "gadget.v1"`,
-		Route:      "/gadgets.v1",
-		Priority:   2,
-	}, s.SaveSDK)
-}
-
-func (s *Service) SaveSDK(ctx context.Context, e *common.TopicEvent) (retry bool, err error) {
-	var gadget gadgets.Gadget
-	if err := e.Struct(&gadget); err != nil {
-		return false, err
-	}
-	if err := s.store.Save(ctx, &gadget); err != nil {
-		return false, err
-	}
-	return false, nil
+func (s *Service) Save(ctx context.Context, gadget *gadgets.Gadget) error {
+	return s.store.Save(ctx, gadget)
 }
@@ -2,17 +2,38 @@ package products
 
 import (
 	"context"
+
+	"github.com/pkedy/golang-dapr/pkg/pagination"
+	"github.com/pkedy/golang-dapr/pkg/validation"
 )
 
 type (
 	Store interface {
 		Load(ctx context.Context, id string) (*Product, error)
+		// Create saves a new product. It returns an `errorz.AlreadyExists`
+		// error if a product with the same ID exists.
+		Create(ctx context.Context, product *Product) error
 		Save(ctx context.Context, product *Product) error
+		Delete(ctx context.Context, id string) error
+		List(ctx context.Context, filter Filter) (*Page, error)
+	}
+
+	// Filter narrows the products returned by `List`.
+	Filter struct {
+		pagination.Request
 	}
 
+	Page = pagination.Page[*Product]
+
 	Product struct {
 		ID          string  `json:"id"`
 		Description string  `json:"description"`
 		Price       float64 `json:"price"`
 	}
 )
+
+// Validate returns an `errorz.InvalidArgument` error
+// with the fields of the product that are invalid.
+func (p *Product) Validate() error {
+	return validation.Item("product", p.ID, p.Description, p.Price)
+}
@@ -7,12 +7,11 @@ import (
 
 	"github.com/go-logr/logr"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 
 	"github.com/pkedy/golang-dapr/pkg/dapr"
 	"github.com/pkedy/golang-dapr/pkg/errorz"
 	"github.com/pkedy/golang-dapr/pkg/features/products"
+	"github.com/pkedy/golang-dapr/pkg/pagination"
 	pb "github.com/pkedy/golang-dapr/proto/products"
 )
 
@@ -32,6 +31,8 @@ func New(log logr.Logger) (*Repository, error) {
 	conn, err := grpc.Dial(GRPCADDRESS,
 		grpc.WithInsecure(),
 		grpc.WithBlock(),
+		// Status errors are returned as `*errorz.Error`.
+		grpc.WithUnaryInterceptor(errorz.UnaryClientInterceptor()),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("could not connect: %v", err)
@@ -49,6 +50,17 @@ func (r *Repository) Close() error {
 	return r.conn.Close()
 }
 
+func (r *Repository) Create(ctx context.Context, product *products.Product) error {
+	r.log.Info("Invoking products service: CreateProduct")
+	ctx = dapr.InvokingContext(ctx, daprAppID)
+	_, err := r.client.CreateProduct(ctx, &pb.Product{
+		Id:          product.ID,
+		Description: product.Description,
+		Price:       product.Price,
+	})
+	return err
+}
+
 func (r *Repository) Save(ctx context.Context, product *products.Product) error {
 	r.log.Info("Invoking products service: SaveProduct")
 	ctx = dapr.InvokingContext(ctx, daprAppID)
@@ -60,15 +72,48 @@ func (r *Repository) Save(ctx context.Context, product *products.Product) error
 	return err
 }
 
+func (r *Repository) Delete(ctx context.Context, id string) error {
+	r.log.Info("Invoking products service: DeleteProduct")
+	ctx = dapr.InvokingContext(ctx, daprAppID)
+	_, err := r.client.DeleteProduct(ctx, &pb.ProductRequest{Id: id})
+	return err
+}
+
+func (r *Repository) List(ctx context.Context, filter products.Filter) (*products.Page, error) {
+	r.log.Info("Invoking products service: ListProducts")
+	ctx = dapr.InvokingContext(ctx, daprAppID)
+	// Products are listed by ID, so the page token is the ID of the last product.
+	req := pb.ListProductsRequest{
+		PageSize: int32(filter.PageLimit()),
+	}
+	if filter.Cursor != nil {
+		req.PageToken = filter.Cursor.ID
+	}
+	resp, err := r.client.ListProducts(ctx, &req)
+	if err != nil {
+		return nil, err
+	}
+	page := products.Page{
+		Items: make([]*products.Product, len(resp.Products)),
+	}
+	if resp.NextPageToken != "" {
+		page.Next = (&pagination.Cursor{ID: resp.NextPageToken}).Encode()
+	}
+	for i, product := range resp.Products {
+		page.Items[i] = &products.Product{
+			ID:          product.Id,
+			Description: product.Description,
+			Price:       product.Price,
+		}
+	}
+	return &page, nil
+}
+
 func (r *Repository) Load(ctx context.Context, id string) (*products.Product, error) {
 	r.log.Info("Invoking products service: GetProduct")
 	ctx = dapr.InvokingContext(ctx, daprAppID)
 	product, err := r.client.GetProduct(ctx, &pb.ProductRequest{Id: id})
 	if err != nil {
-		st, ok := status.FromError(err)
-		if ok && st.Code() == codes.NotFound {
-			return nil, errorz.NotFound(st.Message())
-		}
 		return nil, err
 	}
 	return &products.Product{
@@ -2,38 +2,87 @@ package service
 
 import (
 	"context"
-	"encoding/json"
 
-	pb "github.com/dapr/dapr/pkg/proto/runtime/v1"
-	"github.com/dapr/go-sdk/service/common"
 	"github.com/go-logr/logr"
 	"github.com/gofiber/fiber/v2"
 
 	"github.com/pkedy/golang-dapr/pkg/dapr"
+	"github.com/pkedy/golang-dapr/pkg/errorz"
 	"github.com/pkedy/golang-dapr/pkg/features/products"
+	"github.com/pkedy/golang-dapr/pkg/pagination"
 )
 
 type (
 	Service struct {
+		*dapr.EventHandlers
 		log   logr.Logger
 		store products.Store
 	}
 )
 
 func New(log logr.Logger, store products.Store) *Service {
-	return &Service{
+	s := &Service{
 		log:   log,
 		store: store,
 	}
+	// Default route
+	s.EventHandlers = dapr.NewEventHandlers(log,
+		dapr.Handle("pubsub", "inventory", dapr.Rule{
+			Path: "/products.v1",
+		}, s.Save).WithDeadLetterTopic("inventory-deadletter"),
+	)
+	return s
 }
 
 // SERVICE OPERATIONS
 
 func (s *Service) RegisterService(app *fiber.App) {
+	app.Get("/v1/products", func(c *fiber.Ctx) error {
+		req, err := pagination.FromQuery(c)
+		if err != nil {
+			return err
+		}
+		filter := products.Filter{
+			Request: req,
+		}
+		page, err := s.store.List(c.Context(), filter)
+		return response(c, page, err)
+	})
 	app.Get("/v1/products/:id", func(c *fiber.Ctx) error {
 		product, err := s.store.Load(c.Context(), c.Params("id"))
 		return response(c, product, err)
 	})
+	app.Post("/v1/products", func(c *fiber.Ctx) error {
+		var product products.Product
+		if err := c.BodyParser(&product); err != nil {
+			return errorz.InvalidArgument("invalid product: %v", err)
+		}
+		if err := product.Validate(); err != nil {
+			return err
+		}
+		if err := s.store.Create(c.Context(), &product); err != nil {
+			return err
+		}
+		return response(c.Status(fiber.StatusCreated), &product, nil)
+	})
+	app.Put("/v1/products/:id", func(c *fiber.Ctx) error {
+		var product products.Product
+		if err := c.BodyParser(&product); err != nil {
+			return errorz.InvalidArgument("invalid product: %v", err)
+		}
+		product.ID = c.Params("id")
+		if err := product.Validate(); err != nil {
+			return err
+		}
+		err := s.store.Save(c.Context(), &product)
+		return response(c, &product, err)
+	})
+	app.Delete("/v1/products/:id", func(c *fiber.Ctx) error {
+		if err := s.store.Delete(c.Context(), c.Params("id")); err != nil {
+			return err
+		}
+		return c.SendStatus(fiber.StatusNoContent)
+	})
 }
 
 func response(c *fiber.Ctx, val interface{}, err error) error {
@@ -46,73 +95,6 @@ func response(c *fiber.Ctx, val interface{}, err error) error {
 
 // EVENT HANDLERS
 
-func (s *Service) Subscriptions() []dapr.Subscription {
-	return []dapr.Subscription{
-		{
-			PubsubName: "pubsub",
-			Topic:      "inventory",
-			Routes: dapr.Routes{
-				Default: "/products.v1",
-			},
-		},
-	}
-}
-
-// HTTP
-
-func (s *Service) RegisterEventHandlers(app *fiber.App) {
-	app.Post("/products.v1", s.SaveHTTP)
-}
-
-func (s *Service) SaveHTTP(c *fiber.Ctx) error {
-	var product products.Product
-	if err := dapr.DecodeCloudEvent(c, nil, &product); err != nil {
-		return err
-	}
-	if err := s.store.Save(c.Context(), &product); err != nil {
-		return err
-	}
-	return c.SendString("OK")
-}
-
-// gRPC
-
-func (s *Service) RegisterTopicEventHandlers(register dapr.RegisterEventHandler) {
-	register("/products.v1", s.SaveGRPC)
-}
-
-func (s *Service) SaveGRPC(ctx context.Context, in *pb.TopicEventRequest) (*pb.TopicEventResponse, error) {
-	var gadget products.Product
-	if err := json.Unmarshal(in.Data, &gadget); err != nil {
-		return nil, err
-	}
-	if err := s.store.Save(ctx, &gadget); err != nil {
-		return nil, err
-	}
-
-	return &pb.TopicEventResponse{
-		Status: pb.TopicEventResponse_SUCCESS,
-	}, nil
-}
-
-// SDK
-
-func (s *Service) RegisterTopicEventHandlersSDK(service common.Service) error {
-	// Default route
-	return service.AddTopicEventHandler(&common.Subscription{
-		PubsubName: "pubsub",
-		Topic:      "inventory",
-		Route:      "/products.v1",
-	}, s.SaveSDK)
-}
-
-func (s *Service) SaveSDK(ctx context.Context, e *common.TopicEvent) (retry bool, err error) {
-	var product products.Product
-	if err := e.Struct(&product); err != nil {
-		return false, err
-	}
-	if err := s.store.Save(ctx, &product); err != nil {
-		return false, err
-	}
-	return false, nil
+func (s *Service) Save(ctx context.Context, product *products.Product) error {
+	return s.store.Save(ctx, product)
 }
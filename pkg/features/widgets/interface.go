@@ -2,12 +2,39 @@ package widgets
 
 import (
 	"context"
+	"time"
+
+	"github.com/pkedy/golang-dapr/pkg/pagination"
+	"github.com/pkedy/golang-dapr/pkg/validation"
 )
 
 type (
 	Store interface {
 		Load(ctx context.Context, id string) (*Widget, error)
+		// Create saves a new widget. It returns an `errorz.AlreadyExists`
+		// error if a widget with the same ID exists.
+		Create(ctx context.Context, widget *Widget) error
 		Save(ctx context.Context, widget *Widget) error
+		Delete(ctx context.Context, id string) error
+		List(ctx context.Context, filter Filter) (*Page, error)
+	}
+
+	// Filter narrows the widgets returned by `List`.
+	// Widgets can be sorted by id, price or description.
+	Filter struct {
+		pagination.Request
+		MinPrice          *float64
+		MaxPrice          *float64
+		DescriptionPrefix string
+	}
+
+	Page = pagination.Page[*Widget]
+
+	// Outbox holds widget events until they are published.
+	Outbox interface {
+		// Prune deletes events that were published before `before`
+		// and returns how many were deleted.
+		Prune(ctx context.Context, before time.Time) (int64, error)
 	}
 
 	Widget struct {
@@ -16,3 +43,9 @@ type (
 		Price       float64 `json:"price"`
 	}
 )
+
+// Validate returns an `errorz.InvalidArgument` error
+// with the fields of the widget that are invalid.
+func (w *Widget) Validate() error {
+	return validation.Item("widget", w.ID, w.Description, w.Price)
+}
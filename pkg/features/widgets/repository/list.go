@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/pkedy/golang-dapr/pkg/errorz"
+	"github.com/pkedy/golang-dapr/pkg/features/widgets"
+	"github.com/pkedy/golang-dapr/pkg/pagination"
+)
+
+// Columns that widgets can be sorted by.
+var sortColumns = map[string]string{
+	"":            "id",
+	"id":          "id",
+	"price":       "price",
+	"description": "description",
+}
+
+// List returns a page of widgets using keyset pagination: each page continues
+// after the sort value and ID of the last widget of the previous page, so
+// pages are read from the index instead of skipping rows with OFFSET.
+func (r *Repository) List(ctx context.Context, filter widgets.Filter) (*widgets.Page, error) {
+	r.log.Info("Listing widgets from DB", "filter", filter)
+	sql, args, err := listQuery(filter)
+	if err != nil {
+		return nil, err
+	}
+	column := sortColumns[filter.Sort.Field]
+	limit := filter.PageLimit()
+
+	rows, err := r.pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, errorz.Internal(err, "could not list widgets")
+	}
+	defer rows.Close()
+
+	page := widgets.Page{
+		Items: make([]*widgets.Widget, 0, limit),
+	}
+	for rows.Next() {
+		if len(page.Items) == limit {
+			last := page.Items[limit-1]
+			next := pagination.Cursor{
+				Sort: filter.Sort,
+				ID:   last.ID,
+			}
+			switch column {
+			case "price":
+				next.Value = last.Price
+			case "description":
+				next.Value = last.Description
+			}
+			page.Next = next.Encode()
+			break
+		}
+		var widget widgets.Widget
+		if err := rows.Scan(&widget.ID, &widget.Description, &widget.Price); err != nil {
+			return nil, errorz.Internal(err, "could not read widget")
+		}
+		page.Items = append(page.Items, &widget)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errorz.Internal(err, "could not list widgets")
+	}
+
+	return &page, nil
+}
+
+// listQuery builds the keyset query for `filter`. It reads one
+// widget more than the page limit to know if there is another page.
+func listQuery(filter widgets.Filter) (string, []interface{}, error) {
+	column, ok := sortColumns[filter.Sort.Field]
+	if !ok {
+		return "", nil, errorz.InvalidArgument("cannot sort by %q", filter.Sort.Field)
+	}
+	order, compare := "ASC", ">"
+	if filter.Sort.Desc() {
+		order, compare = "DESC", "<"
+	}
+
+	var where []string
+	var args []interface{}
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return "$" + strconv.Itoa(len(args))
+	}
+	if filter.MinPrice != nil {
+		where = append(where, "price >= "+arg(*filter.MinPrice))
+	}
+	if filter.MaxPrice != nil {
+		where = append(where, "price <= "+arg(*filter.MaxPrice))
+	}
+	if filter.DescriptionPrefix != "" {
+		where = append(where, "description LIKE "+arg(escapeLike(filter.DescriptionPrefix)+"%"))
+	}
+	if cursor := filter.Cursor; cursor != nil {
+		if column == "id" {
+			where = append(where, "id "+compare+" "+arg(cursor.ID))
+		} else {
+			value, err := cursorValue(column, cursor.Value)
+			if err != nil {
+				return "", nil, err
+			}
+			// The ID breaks ties between widgets with the same value.
+			where = append(where, "("+column+", id) "+compare+" ("+arg(value)+", "+arg(cursor.ID)+")")
+		}
+	}
+
+	var sql strings.Builder
+	sql.WriteString("SELECT id, description, price FROM widgets")
+	if len(where) > 0 {
+		sql.WriteString(" WHERE ")
+		sql.WriteString(strings.Join(where, " AND "))
+	}
+	sql.WriteString(" ORDER BY " + column + " " + order)
+	if column != "id" {
+		sql.WriteString(", id " + order)
+	}
+	sql.WriteString(" LIMIT " + arg(filter.PageLimit()+1))
+	return sql.String(), args, nil
+}
+
+// cursorValue checks that the cursor value has the type of `column`.
+func cursorValue(column string, value interface{}) (interface{}, error) {
+	var ok bool
+	switch column {
+	case "price":
+		_, ok = value.(float64)
+	case "description":
+		_, ok = value.(string)
+	}
+	if !ok {
+		return nil, errorz.InvalidArgument("invalid cursor")
+	}
+	return value, nil
+}
+
+// escapeLike escapes the LIKE wildcards in `s`.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
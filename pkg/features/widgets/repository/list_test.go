@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pkedy/golang-dapr/pkg/errorz"
+	"github.com/pkedy/golang-dapr/pkg/features/widgets"
+	"github.com/pkedy/golang-dapr/pkg/pagination"
+)
+
+func TestListQuery(t *testing.T) {
+	minPrice, maxPrice := 1.5, 10.0
+	byPrice := pagination.Sort{Field: "price", Order: pagination.OrderDesc}
+	byID := pagination.Sort{Field: "id", Order: pagination.OrderAsc}
+	tests := []struct {
+		name   string
+		filter widgets.Filter
+		sql    string
+		args   []interface{}
+	}{
+		{
+			name:   "first page",
+			filter: widgets.Filter{},
+			sql:    "SELECT id, description, price FROM widgets ORDER BY id ASC LIMIT $1",
+			args:   []interface{}{pagination.DefaultLimit + 1},
+		},
+		{
+			name: "filters",
+			filter: widgets.Filter{
+				Request:           pagination.Request{Limit: 10},
+				MinPrice:          &minPrice,
+				MaxPrice:          &maxPrice,
+				DescriptionPrefix: `50%_off\`,
+			},
+			sql: "SELECT id, description, price FROM widgets" +
+				" WHERE price >= $1 AND price <= $2 AND description LIKE $3 ORDER BY id ASC LIMIT $4",
+			args: []interface{}{1.5, 10.0, `50\%\_off\\%`, 11},
+		},
+		{
+			name: "after id",
+			filter: widgets.Filter{Request: pagination.Request{
+				Limit:  10,
+				Sort:   byID,
+				Cursor: &pagination.Cursor{Sort: byID, ID: "w1"},
+			}},
+			sql:  "SELECT id, description, price FROM widgets WHERE id > $1 ORDER BY id ASC LIMIT $2",
+			args: []interface{}{"w1", 11},
+		},
+		{
+			name: "after price descending",
+			filter: widgets.Filter{
+				Request: pagination.Request{
+					Limit:  10,
+					Sort:   byPrice,
+					Cursor: &pagination.Cursor{Sort: byPrice, Value: 5.0, ID: "w1"},
+				},
+				MaxPrice: &maxPrice,
+			},
+			sql: "SELECT id, description, price FROM widgets" +
+				" WHERE price <= $1 AND (price, id) < ($2, $3) ORDER BY price DESC, id DESC LIMIT $4",
+			args: []interface{}{10.0, 5.0, "w1", 11},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := listQuery(tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sql != tt.sql {
+				t.Errorf("expected SQL\n%s\ngot\n%s", tt.sql, sql)
+			}
+			if !reflect.DeepEqual(args, tt.args) {
+				t.Errorf("expected arguments %v, got %v", tt.args, args)
+			}
+		})
+	}
+}
+
+func TestListQueryInvalid(t *testing.T) {
+	byPrice := pagination.Sort{Field: "price"}
+	tests := []struct {
+		name   string
+		filter widgets.Filter
+	}{
+		{"sort field", widgets.Filter{Request: pagination.Request{
+			Sort: pagination.Sort{Field: "color"},
+		}}},
+		{"cursor value", widgets.Filter{Request: pagination.Request{
+			Sort:   byPrice,
+			Cursor: &pagination.Cursor{Sort: byPrice, Value: "cheap", ID: "w1"},
+		}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := listQuery(tt.filter); errorz.From(err).Code != 400 {
+				t.Errorf("expected 400, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/go-logr/logr"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"github.com/pkedy/golang-dapr/pkg/components/pubsub"
+	"github.com/pkedy/golang-dapr/pkg/features/widgets"
+)
+
+const (
+	nameSelectPending = "select-pending"
+	// SKIP LOCKED lets each replica claim a different batch.
+	sqlSelectPending = `SELECT id, topic, partition_key, event FROM widget_outbox
+	WHERE delivered_at IS NULL AND parked_at IS NULL
+	ORDER BY id
+	LIMIT $1
+	FOR UPDATE SKIP LOCKED`
+
+	nameMarkDelivered = "mark-delivered"
+	sqlMarkDelivered  = `UPDATE widget_outbox SET delivered_at = now(), attempts = attempts + 1 WHERE id = $1`
+
+	nameMarkFailed = "mark-failed"
+	// Events that fail `$3` times are parked.
+	sqlMarkFailed = `UPDATE widget_outbox SET attempts = attempts + 1, last_error = left($2, 4000),
+	parked_at = CASE WHEN attempts + 1 >= $3 THEN now() END
+	WHERE id = $1
+	RETURNING parked_at IS NOT NULL`
+
+	namePrune = "prune"
+	sqlPrune  = `DELETE FROM widget_outbox WHERE delivered_at < $1`
+)
+
+var _ = widgets.Outbox((*Relay)(nil))
+
+type (
+	// Relay publishes the events in the widget outbox. Events are locked while
+	// they are published so that multiple replicas never send the same event.
+	Relay struct {
+		log         logr.Logger
+		pool        *pgxpool.Pool
+		publisher   pubsub.Publisher
+		pubsubName  string
+		interval    time.Duration
+		batchSize   int
+		maxAttempts int
+	}
+
+	outboxEvent struct {
+		id           int64
+		topic        string
+		partitionKey string
+		event        []byte
+	}
+)
+
+func prepareRelay(ctx context.Context, conn *pgx.Conn) (err error) {
+	if _, err = conn.Prepare(ctx, nameSelectPending, sqlSelectPending); err != nil {
+		return err
+	}
+	if _, err = conn.Prepare(ctx, nameMarkDelivered, sqlMarkDelivered); err != nil {
+		return err
+	}
+	if _, err = conn.Prepare(ctx, nameMarkFailed, sqlMarkFailed); err != nil {
+		return err
+	}
+	if _, err = conn.Prepare(ctx, namePrune, sqlPrune); err != nil {
+		return err
+	}
+	return nil
+}
+
+// NewRelay creates a relay that checks the outbox every `interval`.
+func NewRelay(log logr.Logger, pool *pgxpool.Pool, publisher pubsub.Publisher, pubsubName string, interval time.Duration) *Relay {
+	return &Relay{
+		log:         log,
+		pool:        pool,
+		publisher:   publisher,
+		pubsubName:  pubsubName,
+		interval:    interval,
+		batchSize:   100,
+		maxAttempts: 10,
+	}
+}
+
+// Run publishes events until `ctx` is canceled.
+// Failures are retried with exponential backoff.
+func (r *Relay) Run(ctx context.Context) error {
+	b := backoff.NewExponentialBackOff()
+	b.InitialInterval = r.interval
+	b.MaxElapsedTime = 0 // Never give up
+
+	for {
+		wait := r.interval
+		n, err := r.relay(ctx)
+		if err != nil {
+			wait = b.NextBackOff()
+			r.log.Error(err, "could not relay widget events", "retryIn", wait)
+		} else {
+			b.Reset()
+			if n == r.batchSize {
+				// More events are probably waiting.
+				wait = 0
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(wait):
+		}
+	}
+}
+
+// relay publishes a batch of pending events and returns how many were published.
+func (r *Relay) relay(ctx context.Context) (published int, err error) {
+	var publishErr error
+	err = r.pool.BeginFunc(ctx, func(tx pgx.Tx) (err error) {
+		published, publishErr, err = r.publishBatch(ctx, tx)
+		return err
+	})
+	if err != nil {
+		return published, err
+	}
+	return published, publishErr
+}
+
+// publishBatch claims pending events in `tx` and publishes them. It stops at
+// the first failure so that events are published in order. An event that keeps
+// failing is parked after `maxAttempts` so it does not block the events behind
+// it, which are then published out of order with the parked event.
+//
+// The failed attempt is recorded in `tx`, so `publishErr` does not roll it back.
+func (r *Relay) publishBatch(ctx context.Context, tx pgx.Tx) (published int, publishErr, err error) {
+	rows, err := tx.Query(ctx, nameSelectPending, r.batchSize)
+	if err != nil {
+		return 0, nil, err
+	}
+	var events []outboxEvent
+	for rows.Next() {
+		var e outboxEvent
+		if err := rows.Scan(&e.id, &e.topic, &e.partitionKey, &e.event); err != nil {
+			rows.Close()
+			return 0, nil, err
+		}
+		events = append(events, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, nil, err
+	}
+
+	for _, e := range events {
+		if publishErr = r.publisher.PublishEvent(ctx, r.pubsubName, e.topic, e.event,
+			pubsub.WithCloudEvent(),
+			pubsub.WithPartitionKey(e.partitionKey)); publishErr != nil {
+			// Commit the failed attempt and retry later.
+			var parked bool
+			if err := tx.QueryRow(ctx, nameMarkFailed, e.id, publishErr.Error(), r.maxAttempts).Scan(&parked); err != nil {
+				return published, nil, err
+			}
+			if parked {
+				r.log.Error(publishErr, "parked widget event after too many attempts",
+					"id", e.id, "topic", e.topic, "attempts", r.maxAttempts)
+			}
+			return published, publishErr, nil
+		}
+		if _, err := tx.Exec(ctx, nameMarkDelivered, e.id); err != nil {
+			return published, nil, err
+		}
+		published++
+	}
+	return published, nil, nil
+}
+
+// Prune deletes events that were published before `before`.
+func (r *Relay) Prune(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := r.pool.Exec(ctx, namePrune, before)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+
+	"github.com/pkedy/golang-dapr/pkg/components/pubsub"
+)
+
+type (
+	// fakeOutbox runs the relay statements against events in memory.
+	// Calls that are not implemented panic through the nil embedded `pgx.Tx`.
+	fakeOutbox struct {
+		pgx.Tx
+		events []*fakeEvent
+	}
+
+	fakeEvent struct {
+		outboxEvent
+		attempts  int
+		delivered bool
+		parked    bool
+	}
+
+	fakeRows struct {
+		pgx.Rows
+		events []*fakeEvent
+		next   int
+	}
+
+	fakeRow []interface{}
+
+	// fakePublisher records published topics and fails for topics in `fail`.
+	fakePublisher struct {
+		published []string
+		fail      map[string]bool
+	}
+)
+
+func newFakeOutbox(topics ...string) *fakeOutbox {
+	var o fakeOutbox
+	for i, topic := range topics {
+		o.events = append(o.events, &fakeEvent{outboxEvent: outboxEvent{
+			id:    int64(i + 1),
+			topic: topic,
+		}})
+	}
+	return &o
+}
+
+func (o *fakeOutbox) event(id interface{}) *fakeEvent {
+	return o.events[id.(int64)-1]
+}
+
+func (o *fakeOutbox) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	if sql != nameSelectPending {
+		return nil, errors.New("unexpected query " + sql)
+	}
+	var pending []*fakeEvent
+	for _, e := range o.events {
+		if !e.delivered && !e.parked && len(pending) < args[0].(int) {
+			pending = append(pending, e)
+		}
+	}
+	return &fakeRows{events: pending}, nil
+}
+
+func (o *fakeOutbox) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	if sql != nameMarkFailed {
+		return fakeRow{errors.New("unexpected query " + sql)}
+	}
+	e := o.event(args[0])
+	e.attempts++
+	e.parked = e.attempts >= args[2].(int)
+	return fakeRow{e.parked}
+}
+
+func (o *fakeOutbox) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	if sql != nameMarkDelivered {
+		return nil, errors.New("unexpected statement " + sql)
+	}
+	e := o.event(args[0])
+	e.attempts++
+	e.delivered = true
+	return pgconn.CommandTag("UPDATE 1"), nil
+}
+
+func (r *fakeRows) Next() bool {
+	r.next++
+	return r.next <= len(r.events)
+}
+
+func (r *fakeRows) Scan(dest ...interface{}) error {
+	e := r.events[r.next-1]
+	*dest[0].(*int64) = e.id
+	*dest[1].(*string) = e.topic
+	*dest[2].(*string) = e.partitionKey
+	*dest[3].(*[]byte) = e.event
+	return nil
+}
+
+func (r *fakeRows) Close()     {}
+func (r *fakeRows) Err() error { return nil }
+
+func (r fakeRow) Scan(dest ...interface{}) error {
+	if err, ok := r[0].(error); ok {
+		return err
+	}
+	*dest[0].(*bool) = r[0].(bool)
+	return nil
+}
+
+func (p *fakePublisher) PublishEvent(ctx context.Context, pubsubName string, topic string, data interface{}, opts ...pubsub.Option) error {
+	if p.fail[topic] {
+		return errors.New("could not publish to " + topic)
+	}
+	p.published = append(p.published, topic)
+	return nil
+}
+
+func newTestRelay(publisher pubsub.Publisher) *Relay {
+	return &Relay{
+		log:         logr.Discard(),
+		publisher:   publisher,
+		pubsubName:  "pubsub",
+		batchSize:   2,
+		maxAttempts: 3,
+	}
+}
+
+func TestRelayPublishesInBatches(t *testing.T) {
+	outbox := newFakeOutbox("a", "b", "c")
+	publisher := &fakePublisher{}
+	r := newTestRelay(publisher)
+	for _, want := range []int{2, 1, 0} {
+		published, publishErr, err := r.publishBatch(context.Background(), outbox)
+		if err != nil || publishErr != nil {
+			t.Fatal(err, publishErr)
+		}
+		if published != want {
+			t.Errorf("expected %d events to be published, got %d", want, published)
+		}
+	}
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(publisher.published, want) {
+		t.Errorf("expected %v to be published, got %v", want, publisher.published)
+	}
+}
+
+func TestRelayStopsAtFailure(t *testing.T) {
+	outbox := newFakeOutbox("a", "b", "c")
+	publisher := &fakePublisher{fail: map[string]bool{"b": true}}
+	r := newTestRelay(publisher)
+	published, publishErr, err := r.publishBatch(context.Background(), outbox)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if published != 1 || publishErr == nil {
+		t.Fatalf("expected 1 event and a publish error, got %d and %v", published, publishErr)
+	}
+	if b := outbox.events[1]; b.attempts != 1 || b.delivered || b.parked {
+		t.Errorf("expected one failed attempt for b, got %+v", b)
+	}
+	if c := outbox.events[2]; c.attempts != 0 {
+		t.Errorf("expected c to wait for b, got %d attempts", c.attempts)
+	}
+}
+
+func TestRelayParksFailingEvents(t *testing.T) {
+	outbox := newFakeOutbox("a", "b")
+	publisher := &fakePublisher{fail: map[string]bool{"a": true}}
+	r := newTestRelay(publisher)
+	for i := 1; i <= r.maxAttempts; i++ {
+		published, publishErr, err := r.publishBatch(context.Background(), outbox)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if published != 0 || publishErr == nil {
+			t.Fatalf("attempt %d: expected a publish error, got %d events and %v", i, published, publishErr)
+		}
+	}
+	if a := outbox.events[0]; !a.parked || a.attempts != r.maxAttempts {
+		t.Fatalf("expected a to be parked after %d attempts, got %+v", r.maxAttempts, a)
+	}
+
+	// The parked event no longer blocks the events behind it.
+	published, publishErr, err := r.publishBatch(context.Background(), outbox)
+	if err != nil || publishErr != nil {
+		t.Fatal(err, publishErr)
+	}
+	if published != 1 || !reflect.DeepEqual(publisher.published, []string{"b"}) {
+		t.Errorf("expected b to be published, got %v", publisher.published)
+	}
+}
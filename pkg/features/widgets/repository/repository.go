@@ -2,12 +2,14 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 
 	"github.com/go-logr/logr"
 	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 
+	"github.com/pkedy/golang-dapr/pkg/dapr"
 	"github.com/pkedy/golang-dapr/pkg/errorz"
 	"github.com/pkedy/golang-dapr/pkg/features/widgets"
 )
@@ -19,8 +21,27 @@ const (
 	ON CONFLICT ON CONSTRAINT widgets_pkey
 	DO UPDATE SET description = $2, price = $3;`
 
+	nameInsert = "insert"
+	sqlInsert  = `INSERT INTO widgets (id, description, price)
+	VALUES ($1, $2, $3)
+	ON CONFLICT ON CONSTRAINT widgets_pkey DO NOTHING`
+
 	nameSelect = "select"
 	sqlSelect  = `SELECT description, price FROM widgets WHERE id = $1`
+
+	nameDelete = "delete"
+	sqlDelete  = `DELETE FROM widgets WHERE id = $1`
+
+	nameInsertOutbox = "insert-outbox"
+	sqlInsertOutbox  = `INSERT INTO widget_outbox (topic, partition_key, event)
+	VALUES ($1, $2, $3)`
+)
+
+const (
+	eventSource      = "inventory"
+	eventTypeChanged = "widget.changed.v1"
+	eventTypeDeleted = "widget.deleted.v1"
+	topicWidgets     = "widgets"
 )
 
 type Repository struct {
@@ -39,21 +60,117 @@ func AfterConnect(ctx context.Context, conn *pgx.Conn) (err error) {
 	if _, err = conn.Prepare(ctx, nameUpsert, sqlUpsert); err != nil {
 		return err
 	}
+	if _, err = conn.Prepare(ctx, nameInsert, sqlInsert); err != nil {
+		return err
+	}
 	if _, err = conn.Prepare(ctx, nameSelect, sqlSelect); err != nil {
 		return err
 	}
-	return nil
+	if _, err = conn.Prepare(ctx, nameDelete, sqlDelete); err != nil {
+		return err
+	}
+	if _, err = conn.Prepare(ctx, nameInsertOutbox, sqlInsertOutbox); err != nil {
+		return err
+	}
+	return prepareRelay(ctx, conn)
 }
 
+// Save upserts the widget and writes a `widget.changed.v1` event to the
+// outbox in the same transaction. The event is published by the `Relay`.
 func (r *Repository) Save(ctx context.Context, widget *widgets.Widget) error {
 	r.log.Info("Saving widget to DB", "widget", widget)
-	if _, err := r.pool.Exec(ctx, nameUpsert, widget.ID, widget.Description, widget.Price); err != nil {
+	event, err := newEvent(ctx, eventTypeChanged, widget)
+	if err != nil {
+		return errorz.Internal(err, "could not create event for widget %q", widget.ID)
+	}
+
+	if err := r.pool.BeginFunc(ctx, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, nameUpsert, widget.ID, widget.Description, widget.Price); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, nameInsertOutbox, topicWidgets, widget.ID, event)
+		return err
+	}); err != nil {
 		r.log.Error(err, "error saving widget", "widget", widget)
 		return errorz.Internal(err, "could not save widget %q", widget.ID)
 	}
 	return nil
 }
 
+// Create inserts the widget and writes a `widget.changed.v1` event
+// to the outbox in the same transaction.
+func (r *Repository) Create(ctx context.Context, widget *widgets.Widget) error {
+	r.log.Info("Creating widget in DB", "widget", widget)
+	event, err := newEvent(ctx, eventTypeChanged, widget)
+	if err != nil {
+		return errorz.Internal(err, "could not create event for widget %q", widget.ID)
+	}
+
+	errExists := errorz.AlreadyExists("widget %q already exists", widget.ID)
+	if err := r.pool.BeginFunc(ctx, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, nameInsert, widget.ID, widget.Description, widget.Price)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return errExists
+		}
+		_, err = tx.Exec(ctx, nameInsertOutbox, topicWidgets, widget.ID, event)
+		return err
+	}); err != nil {
+		if err == errExists {
+			return err
+		}
+		r.log.Error(err, "error creating widget", "widget", widget)
+		return errorz.Internal(err, "could not create widget %q", widget.ID)
+	}
+	return nil
+}
+
+// Delete removes the widget and writes a `widget.deleted.v1` event
+// to the outbox in the same transaction.
+func (r *Repository) Delete(ctx context.Context, id string) error {
+	r.log.Info("Deleting widget from DB", "id", id)
+	event, err := newEvent(ctx, eventTypeDeleted, &widgets.Widget{ID: id})
+	if err != nil {
+		return errorz.Internal(err, "could not create event for widget %q", id)
+	}
+
+	errNotFound := errorz.NotFound("widget with id %q was not found", id)
+	if err := r.pool.BeginFunc(ctx, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, nameDelete, id)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return errNotFound
+		}
+		_, err = tx.Exec(ctx, nameInsertOutbox, topicWidgets, id, event)
+		return err
+	}); err != nil {
+		if err == errNotFound {
+			return err
+		}
+		r.log.Error(err, "error deleting widget", "id", id)
+		return errorz.Internal(err, "could not delete widget %q", id)
+	}
+	return nil
+}
+
+// newEvent returns the CloudEvent for the outbox.
+func newEvent(ctx context.Context, eventType string, widget *widgets.Widget) ([]byte, error) {
+	event, err := dapr.NewCloudEvent(eventSource, eventType, widget)
+	if err != nil {
+		return nil, err
+	}
+	// Continue the trace of the event that triggered the save.
+	if in, ok := dapr.EventFromContext(ctx); ok {
+		event.SetExtension(dapr.ExtensionTraceParent, in.Extension(dapr.ExtensionTraceParent))
+		event.SetExtension(dapr.ExtensionTraceState, in.Extension(dapr.ExtensionTraceState))
+	}
+	return json.Marshal(event)
+}
+
 func (r *Repository) Load(ctx context.Context, id string) (*widgets.Widget, error) {
 	r.log.Info("Loading widget from DB", "id", id)
 	row := r.pool.QueryRow(ctx, nameSelect, id)
@@ -2,123 +2,164 @@ package service
 
 import (
 	"context"
-	"encoding/json"
+	"strconv"
+	"time"
 
-	pb "github.com/dapr/dapr/pkg/proto/runtime/v1"
-	"github.com/dapr/go-sdk/service/common"
 	"github.com/go-logr/logr"
 	"github.com/gofiber/fiber/v2"
 
 	"github.com/pkedy/golang-dapr/pkg/dapr"
+	"github.com/pkedy/golang-dapr/pkg/errorz"
 	"github.com/pkedy/golang-dapr/pkg/features/widgets"
+	"github.com/pkedy/golang-dapr/pkg/pagination"
+)
+
+const (
+	pubsubName      = "pubsub"
+	topicDeadLetter = "inventory-deadletter"
+
+	// bindingPruneOutbox is a cron input binding.
+	bindingPruneOutbox = "widgets-outbox-prune"
+	outboxRetention    = 7 * 24 * time.Hour
 )
 
 type (
 	Service struct {
-		log   logr.Logger
-		store widgets.Store
+		*dapr.EventHandlers
+		log    logr.Logger
+		store  widgets.Store
+		outbox widgets.Outbox
 	}
 )
 
-func New(log logr.Logger, store widgets.Store) *Service {
-	return &Service{
-		log:   log,
-		store: store,
+// New creates the widgets service. Published
+// events are periodically pruned from `outbox`.
+func New(log logr.Logger, store widgets.Store, outbox widgets.Outbox) *Service {
+	s := &Service{
+		log:    log,
+		store:  store,
+		outbox: outbox,
 	}
+	s.EventHandlers = dapr.NewEventHandlers(log,
+		dapr.Handle(pubsubName, "inventory", dapr.Rule{
+			Match: `event.type == "widget.v1"`,
+			Path:  "/widgets.v1",
+		}, s.Save).WithPriority(1).WithDeadLetterTopic(topicDeadLetter),
+	)
+	return s
 }
 
 // SERVICE OPERATIONS
 
 func (s *Service) RegisterService(app *fiber.App) {
+	app.Get("/v1/widgets", func(c *fiber.Ctx) error {
+		req, err := pagination.FromQuery(c, "id", "price", "description")
+		if err != nil {
+			return err
+		}
+		filter := widgets.Filter{
+			Request:           req,
+			DescriptionPrefix: c.Query("descriptionPrefix"),
+		}
+		if filter.MinPrice, err = queryFloat(c, "minPrice"); err != nil {
+			return err
+		}
+		if filter.MaxPrice, err = queryFloat(c, "maxPrice"); err != nil {
+			return err
+		}
+		page, err := s.store.List(c.Context(), filter)
+		return response(c, page, err)
+	})
 	app.Get("/v1/widgets/:id", func(c *fiber.Ctx) error {
 		widget, err := s.store.Load(c.Context(), c.Params("id"))
 		return response(c, widget, err)
 	})
+	app.Post("/v1/widgets", func(c *fiber.Ctx) error {
+		var widget widgets.Widget
+		if err := c.BodyParser(&widget); err != nil {
+			return errorz.InvalidArgument("invalid widget: %v", err)
+		}
+		if err := widget.Validate(); err != nil {
+			return err
+		}
+		if err := s.store.Create(c.Context(), &widget); err != nil {
+			return err
+		}
+		return response(c.Status(fiber.StatusCreated), &widget, nil)
+	})
+	app.Put("/v1/widgets/:id", func(c *fiber.Ctx) error {
+		var widget widgets.Widget
+		if err := c.BodyParser(&widget); err != nil {
+			return errorz.InvalidArgument("invalid widget: %v", err)
+		}
+		widget.ID = c.Params("id")
+		if err := widget.Validate(); err != nil {
+			return err
+		}
+		err := s.store.Save(c.Context(), &widget)
+		return response(c, &widget, err)
+	})
+	app.Delete("/v1/widgets/:id", func(c *fiber.Ctx) error {
+		if err := s.store.Delete(c.Context(), c.Params("id")); err != nil {
+			return err
+		}
+		return c.SendStatus(fiber.StatusNoContent)
+	})
 }
 
-func response(c *fiber.Ctx, val interface{}, err error) error {
-	if err != nil {
-		return err
-	}
-	//return c.Format(val)
-	return c.JSON(val)
-}
+// SERVICE INVOCATION
 
-// EVENT HANDLERS
+func (s *Service) RegisterInvokeHandlers(register dapr.RegisterInvokeHandler) {
+	register(fiber.MethodGet, "widgets/get", s.GetInvoke)
+}
 
-func (s *Service) Subscriptions() []dapr.Subscription {
-	return []dapr.Subscription{
-		{
-			PubsubName: "pubsub",
-			Topic:      "inventory",
-			Routes: dapr.Routes{
-				Rules: []dapr.Rule{
-					{
-						Match: `event.type == "widget.v1"`,
-						Path:  "/widgets.v1",
-					},
-				},
-			},
-		},
+func (s *Service) GetInvoke(ctx context.Context, in *dapr.Invocation) (*dapr.InvocationResult, error) {
+	widget, err := s.store.Load(ctx, in.Query.Get("id"))
+	if err != nil {
+		return nil, err
 	}
+	return dapr.JSONResult(widget)
 }
 
-// HTTP
+// INPUT BINDINGS
 
-func (s *Service) RegisterEventHandlers(app *fiber.App) {
-	app.Post("/widgets.v1", s.SaveHTTP)
+func (s *Service) RegisterBindingHandlers(register dapr.RegisterBindingHandler) {
+	register(bindingPruneOutbox, s.PruneOutbox)
 }
 
-func (s *Service) SaveHTTP(c *fiber.Ctx) error {
-	var widget widgets.Widget
-	if err := dapr.DecodeCloudEvent(c, nil, &widget); err != nil {
-		return err
-	}
-	if err := s.store.Save(c.Context(), &widget); err != nil {
-		return err
+// PruneOutbox deletes events that were published more than a week ago.
+func (s *Service) PruneOutbox(ctx context.Context, in *dapr.BindingEvent) ([]byte, error) {
+	pruned, err := s.outbox.Prune(ctx, time.Now().Add(-outboxRetention))
+	if err != nil {
+		return nil, errorz.Internal(err, "could not prune widget outbox")
 	}
-	return c.SendString("OK")
+	s.log.Info("Pruned widget outbox", "events", pruned)
+	return nil, nil
 }
 
-func (s *Service) RegisterTopicEventHandlers(register dapr.RegisterEventHandler) {
-	register("/widgets.v1", s.SaveGRPC)
-}
-
-// gRPC
-
-func (s *Service) SaveGRPC(ctx context.Context, in *pb.TopicEventRequest) (*pb.TopicEventResponse, error) {
-	var widget widgets.Widget
-	if err := json.Unmarshal(in.Data, &widget); err != nil {
-		return nil, err
+// queryFloat returns the query parameter `key`, or nil if it is not set.
+func queryFloat(c *fiber.Ctx, key string) (*float64, error) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, nil
 	}
-	if err := s.store.Save(ctx, &widget); err != nil {
-		return nil, err
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, errorz.InvalidArgument("invalid %s %q", key, value)
 	}
-
-	return &pb.TopicEventResponse{
-		Status: pb.TopicEventResponse_SUCCESS,
-	}, nil
+	return &f, nil
 }
 
-// SDK
-
-func (s *Service) RegisterTopicEventHandlersSDK(service common.Service) error {
-	return service.AddTopicEventHandler(&common.Subscription{
-		PubsubName: "pubsub",
-		Topic:      "inventory",
-		Match:      `event.type == "widget.v1"`,
-		Route:      "/widgets.v1",
-		Priority:   1,
-	}, s.SaveSDK)
+func response(c *fiber.Ctx, val interface{}, err error) error {
+	if err != nil {
+		return err
+	}
+	//return c.Format(val)
+	return c.JSON(val)
 }
 
-func (s *Service) SaveSDK(ctx context.Context, e *common.TopicEvent) (retry bool, err error) {
-	var widget widgets.Widget
-	if err := e.Struct(&widget); err != nil {
-		return false, err
-	}
-	if err := s.store.Save(ctx, &widget); err != nil {
-		return false, err
-	}
-	return false, nil
+// EVENT HANDLERS
+
+func (s *Service) Save(ctx context.Context, widget *widgets.Widget) error {
+	return s.store.Save(ctx, widget)
 }
@@ -0,0 +1,153 @@
+// Package pagination decodes list requests and encodes opaque
+// cursors the same way for every feature.
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/pkedy/golang-dapr/pkg/errorz"
+)
+
+const (
+	DefaultLimit = 100
+	MaxLimit     = 1000
+)
+
+type (
+	Order string
+
+	// Sort orders items by `Field`. An empty field is the store's default order.
+	Sort struct {
+		Field string `json:"f,omitempty"`
+		Order Order  `json:"o,omitempty"`
+	}
+
+	// Request is a request for a page of items.
+	// `Cursor` is nil for the first page.
+	Request struct {
+		Limit  int
+		Sort   Sort
+		Cursor *Cursor
+	}
+
+	// Cursor is the position after the last item of a page.
+	// Keyset paginated stores use the sort value and ID of the last item.
+	// Stores that have their own continuation token use `Token`.
+	Cursor struct {
+		Sort  Sort        `json:"s,omitempty"`
+		Value interface{} `json:"v,omitempty"`
+		ID    string      `json:"id,omitempty"`
+		Token string      `json:"t,omitempty"`
+	}
+
+	// Page is a page of items. `Next` is the cursor
+	// for the next page and is empty on the last page.
+	Page[T any] struct {
+		Items []T    `json:"items"`
+		Next  string `json:"next,omitempty"`
+	}
+)
+
+const (
+	OrderAsc  Order = "asc"
+	OrderDesc Order = "desc"
+)
+
+// FromQuery decodes the `limit`, `cursor` and `sort` query parameters.
+// `sortFields` are the fields that the items can be sorted by. `sort` is
+// a field name, prefixed with `-` for descending order (i.e. `-price`).
+func FromQuery(c *fiber.Ctx, sortFields ...string) (Request, error) {
+	var req Request
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 0 {
+			return req, invalidArgument("invalid limit %q", limit)
+		}
+		req.Limit = n
+	}
+	if sort := c.Query("sort"); sort != "" {
+		s, err := ParseSort(sort, sortFields...)
+		if err != nil {
+			return req, err
+		}
+		req.Sort = s
+	}
+	if cursor := c.Query("cursor"); cursor != "" {
+		cur, err := DecodeCursor(cursor)
+		if err != nil {
+			return req, err
+		}
+		// A cursor is only valid for the order it was created with.
+		if cur.Sort != req.Sort {
+			return req, invalidArgument("cursor does not match sort %q", c.Query("sort"))
+		}
+		req.Cursor = cur
+	}
+	return req, nil
+}
+
+// ParseSort parses `field` or `-field` where field is one of `allowed`.
+func ParseSort(s string, allowed ...string) (Sort, error) {
+	sort := Sort{
+		Field: s,
+		Order: OrderAsc,
+	}
+	if strings.HasPrefix(s, "-") {
+		sort.Field = s[1:]
+		sort.Order = OrderDesc
+	}
+	for _, field := range allowed {
+		if field == sort.Field {
+			return sort, nil
+		}
+	}
+	return Sort{}, invalidArgument("cannot sort by %q", sort.Field)
+}
+
+// PageLimit returns the limit, or `DefaultLimit` if it is
+// not set. Limits are capped at `MaxLimit`.
+func (r *Request) PageLimit() int {
+	switch {
+	case r.Limit <= 0:
+		return DefaultLimit
+	case r.Limit > MaxLimit:
+		return MaxLimit
+	}
+	return r.Limit
+}
+
+// Desc returns true for descending order.
+func (s Sort) Desc() bool {
+	return s.Order == OrderDesc
+}
+
+// Encode returns the cursor as an opaque string.
+func (c *Cursor) Encode() string {
+	if c == nil {
+		return ""
+	}
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeCursor decodes a cursor from `Encode`.
+func DecodeCursor(s string) (*Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, invalidArgument("invalid cursor")
+	}
+	var cursor Cursor
+	if err := json.Unmarshal(b, &cursor); err != nil {
+		return nil, invalidArgument("invalid cursor")
+	}
+	return &cursor, nil
+}
+
+func invalidArgument(format string, args ...interface{}) *errorz.Error {
+	return errorz.InvalidArgument(format, args...)
+}
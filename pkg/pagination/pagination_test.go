@@ -0,0 +1,106 @@
+package pagination
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/pkedy/golang-dapr/pkg/errorz"
+)
+
+// fromQuery runs `FromQuery` for a request with the query string `query`.
+func fromQuery(t *testing.T, query string) (Request, error) {
+	t.Helper()
+	var (
+		req Request
+		err error
+	)
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		req, err = FromQuery(c, "id", "price")
+		return nil
+	})
+	if _, testErr := app.Test(httptest.NewRequest("GET", "/?"+query, nil)); testErr != nil {
+		t.Fatal(testErr)
+	}
+	return req, err
+}
+
+func TestFromQuery(t *testing.T) {
+	byPrice := Sort{Field: "price", Order: OrderDesc}
+	cursor := (&Cursor{Sort: byPrice, Value: 5.0, ID: "w1"}).Encode()
+	req, err := fromQuery(t, "limit=10&sort=-price&cursor="+cursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Limit != 10 || req.Sort != byPrice {
+		t.Errorf("expected limit 10 sorted by descending price, got %+v", req)
+	}
+	if c := req.Cursor; c == nil || c.Sort != byPrice || c.Value != 5.0 || c.ID != "w1" {
+		t.Errorf("expected the decoded cursor, got %+v", c)
+	}
+
+	req, err = fromQuery(t, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Limit != 0 || req.Sort != (Sort{}) || req.Cursor != nil {
+		t.Errorf("expected an empty request, got %+v", req)
+	}
+}
+
+func TestFromQueryInvalid(t *testing.T) {
+	byID := (&Cursor{Sort: Sort{Field: "id", Order: OrderAsc}, ID: "w1"}).Encode()
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"limit", "limit=ten"},
+		{"negative limit", "limit=-1"},
+		{"sort field", "sort=color"},
+		{"cursor encoding", "cursor=%21%21"},
+		{"cursor JSON", "cursor=bm90IGpzb24"},
+		{"cursor sort", "sort=price&cursor=" + byID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := fromQuery(t, tt.query); errorz.From(err).Code != 400 {
+				t.Errorf("expected 400, got %v", err)
+			}
+		})
+	}
+}
+
+func TestPageLimit(t *testing.T) {
+	tests := []struct {
+		limit int
+		want  int
+	}{
+		{0, DefaultLimit},
+		{-1, DefaultLimit},
+		{10, 10},
+		{MaxLimit + 1, MaxLimit},
+	}
+	for _, tt := range tests {
+		r := Request{Limit: tt.limit}
+		if limit := r.PageLimit(); limit != tt.want {
+			t.Errorf("expected limit %d for %d, got %d", tt.want, tt.limit, limit)
+		}
+	}
+}
+
+func TestCursorEncode(t *testing.T) {
+	var nilCursor *Cursor
+	if s := nilCursor.Encode(); s != "" {
+		t.Errorf("expected no cursor, got %q", s)
+	}
+	cursor := Cursor{Sort: Sort{Field: "description", Order: OrderAsc}, Value: "widget", ID: "w1", Token: "t"}
+	decoded, err := DecodeCursor(cursor.Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *decoded != cursor {
+		t.Errorf("expected %+v, got %+v", cursor, *decoded)
+	}
+}
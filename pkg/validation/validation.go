@@ -0,0 +1,39 @@
+// Package validation checks the fields shared by the inventory items.
+package validation
+
+import (
+	"math"
+	"unicode/utf8"
+
+	"github.com/pkedy/golang-dapr/pkg/errorz"
+)
+
+// The maximum lengths of an item's fields in characters. Products are
+// routed to a store by their type, so every store has the same limits:
+// those of the smallest store.
+const (
+	MaxIDLength          = 64
+	MaxDescriptionLength = 1000
+)
+
+// Item returns an `errorz.InvalidArgument` error with the fields of the
+// item that are invalid. `kind` names the item in the error message.
+func Item(kind, id, description string, price float64) error {
+	var violations errorz.FieldViolations
+	switch {
+	case id == "":
+		violations.Add("id", "is required")
+	case utf8.RuneCountInString(id) > MaxIDLength:
+		violations.Add("id", "must be at most %d characters", MaxIDLength)
+	}
+	if utf8.RuneCountInString(description) > MaxDescriptionLength {
+		violations.Add("description", "must be at most %d characters", MaxDescriptionLength)
+	}
+	switch {
+	case math.IsNaN(price) || math.IsInf(price, 0):
+		violations.Add("price", "must be a number")
+	case price < 0:
+		violations.Add("price", "must not be negative")
+	}
+	return violations.Err("invalid %s %q", kind, id)
+}
@@ -0,0 +1,51 @@
+package validation
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pkedy/golang-dapr/pkg/errorz"
+)
+
+func TestItem(t *testing.T) {
+	tests := []struct {
+		name        string
+		id          string
+		description string
+		price       float64
+		fields      []string
+	}{
+		{"valid", "w1", "Widget", 1, nil},
+		{"at the limits", strings.Repeat("é", MaxIDLength), strings.Repeat("é", MaxDescriptionLength), 0, nil},
+		{"missing id", "", "Widget", 1, []string{"id"}},
+		{"long id", strings.Repeat("a", MaxIDLength+1), "Widget", 1, []string{"id"}},
+		{"long description", "w1", strings.Repeat("a", MaxDescriptionLength+1), 1, []string{"description"}},
+		{"negative price", "w1", "Widget", -1, []string{"price"}},
+		{"NaN price", "w1", "Widget", math.NaN(), []string{"price"}},
+		{"all fields", "", strings.Repeat("a", MaxDescriptionLength+1), math.Inf(1), []string{"id", "description", "price"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Item("widget", tt.id, tt.description, tt.price)
+			if tt.fields == nil {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+			errz := errorz.From(err)
+			if errz.Code != 400 {
+				t.Fatalf("expected 400, got %v", err)
+			}
+			var fields []string
+			for _, v := range errz.Details.(errorz.FieldViolations) {
+				fields = append(fields, v.Field)
+			}
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("expected violations for %v, got %v", tt.fields, errz.Details)
+			}
+		})
+	}
+}
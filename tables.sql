@@ -2,4 +2,26 @@ CREATE TABLE widgets (
 	id varchar(256) PRIMARY KEY,
 	description varchar(4000) NOT NULL,
 	price float NOT NULL
-);
\ No newline at end of file
+);
+
+-- Keyset pagination when listing widgets sorted by price or description.
+CREATE INDEX widgets_price ON widgets (price, id);
+CREATE INDEX widgets_description ON widgets (description, id);
+
+-- Events for widget changes are written in the same transaction
+-- as the widget and published by the outbox relay.
+CREATE TABLE widget_outbox (
+	id bigserial PRIMARY KEY,
+	topic varchar(256) NOT NULL,
+	partition_key varchar(256) NOT NULL,
+	event jsonb NOT NULL,
+	created_at timestamptz NOT NULL DEFAULT now(),
+	attempts int NOT NULL DEFAULT 0,
+	last_error varchar(4000),
+	delivered_at timestamptz,
+	-- Set when the relay gives up on the event. Parked events are
+	-- not published or pruned until they are reset by an operator.
+	parked_at timestamptz
+);
+
+CREATE INDEX widget_outbox_pending ON widget_outbox (id) WHERE delivered_at IS NULL AND parked_at IS NULL;